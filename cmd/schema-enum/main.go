@@ -0,0 +1,53 @@
+// Command schema-enum generates the schema.RegisterEnum calls for the constants of named types
+//
+// Usage with go generate, in the package that declares the type:
+//
+//	//go:generate go run github.com/s-augustovitko/mongo-schema-go/cmd/schema-enum -type Status,Priority
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/s-augustovitko/mongo-schema-go/internal/enumgen"
+	"github.com/s-augustovitko/mongo-schema-go/internal/tags"
+)
+
+func main() {
+	typeNames := flag.String("type", "", "comma separated names of the types, required")
+	output := flag.String("output", "", "output file name, default <dir>/<first type>_enum.go")
+	flag.Parse()
+
+	log.SetFlags(0)
+	log.SetPrefix("schema-enum: ")
+
+	types := []string{}
+	for _, name := range tags.SplitTrim(*typeNames, ",") {
+		if name != "" {
+			types = append(types, name)
+		}
+	}
+	if len(types) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	if *output == "" {
+		*output = filepath.Join(dir, strings.ToLower(types[0])+"_enum.go")
+	}
+
+	src, err := enumgen.Run(dir, types)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,155 @@
+package enumgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+const schemaPkg = "github.com/s-augustovitko/mongo-schema-go/pkg/schema"
+
+// Constants of a named type declared in a package
+type Enum struct {
+	Type   string
+	Values []string
+}
+
+// Creates the source of the file that registers the enums of typeNames for the package in dir
+func Run(dir string, typeNames []string) ([]byte, error) {
+	pkg, files, err := ParseDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	enums, err := Collect(files, typeNames)
+	if err != nil {
+		return nil, err
+	}
+	return Generate(pkg, enums)
+}
+
+// Parses the go files of the package in dir, test files and files excluded by their build constraints
+// (eg. //go:build ignore or a _windows.go suffix on linux) are ignored, like the go tool does for the current platform
+func ParseDir(dir string) (string, []*ast.File, error) {
+	fset := token.NewFileSet()
+	filter := func(info fs.FileInfo) bool {
+		if strings.HasSuffix(info.Name(), "_test.go") {
+			return false
+		}
+		match, err := build.Default.MatchFile(dir, info.Name())
+		return err == nil && match
+	}
+	pkgs, err := parser.ParseDir(fset, dir, filter, 0)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(pkgs) != 1 {
+		return "", nil, fmt.Errorf("expected one package in [%v], found %v", dir, len(pkgs))
+	}
+
+	for name, pkg := range pkgs {
+		// Sorted by file name so the values keep the same order between runs
+		fileNames := make([]string, 0, len(pkg.Files))
+		for fileName := range pkg.Files {
+			fileNames = append(fileNames, fileName)
+		}
+		sort.Strings(fileNames)
+
+		files := make([]*ast.File, 0, len(fileNames))
+		for _, fileName := range fileNames {
+			files = append(files, pkg.Files[fileName])
+		}
+		return name, files, nil
+	}
+	return "", nil, nil
+}
+
+// Collects the names of the constants of each type in typeNames
+// constants without a type in a const block take the type of the previous constant (eg. with iota)
+// unexported constants are collected too since the generated file is part of the package,
+// blank constants are skipped and a type without constants is an error
+func Collect(files []*ast.File, typeNames []string) ([]Enum, error) {
+	values := map[string][]string{}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.CONST {
+				continue
+			}
+			collectDecl(genDecl, values)
+		}
+	}
+
+	out := make([]Enum, 0, len(typeNames))
+	for _, name := range typeNames {
+		if len(values[name]) == 0 {
+			return nil, fmt.Errorf("no constants of type [%v] were found", name)
+		}
+		out = append(out, Enum{Type: name, Values: values[name]})
+	}
+	return out, nil
+}
+
+// Adds the constants of the declaration to values by their type name
+func collectDecl(decl *ast.GenDecl, values map[string][]string) {
+	typeName := ""
+	for _, spec := range decl.Specs {
+		valueSpec := spec.(*ast.ValueSpec)
+		// A spec with values and no type starts untyped constants, one without values repeats the previous spec
+		if valueSpec.Type != nil {
+			typeName = ""
+			if ident, ok := valueSpec.Type.(*ast.Ident); ok {
+				typeName = ident.Name
+			}
+		} else if len(valueSpec.Values) > 0 {
+			typeName = ""
+		}
+		if typeName == "" {
+			continue
+		}
+
+		for _, name := range valueSpec.Names {
+			if name.Name != "_" {
+				values[typeName] = append(values[typeName], name.Name)
+			}
+		}
+	}
+}
+
+var fileTemplate = template.Must(template.New("enum").Parse(`// Code generated by schema-enum; DO NOT EDIT.
+
+package {{ .Package }}
+
+import "` + schemaPkg + `"
+
+func init() {
+{{- range .Enums }}
+	if err := schema.RegisterEnum({{ range $i, $val := .Values }}{{ if $i }}, {{ end }}{{ $val }}{{ end }}); err != nil {
+		panic(err)
+	}
+{{- end }}
+}
+`))
+
+// Creates the source of a file of the package that registers the enums in its init function
+func Generate(pkg string, enums []Enum) ([]byte, error) {
+	enums = append([]Enum{}, enums...)
+	sort.Slice(enums, func(i, j int) bool { return enums[i].Type < enums[j].Type })
+
+	var buf bytes.Buffer
+	err := fileTemplate.Execute(&buf, struct {
+		Package string
+		Enums   []Enum
+	}{pkg, enums})
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
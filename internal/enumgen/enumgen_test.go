@@ -0,0 +1,132 @@
+package enumgen
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"testing"
+)
+
+const testSource = `package models
+
+type Status string
+
+type Priority int
+
+const (
+	StatusOpen   Status = "open"
+	StatusClosed Status = "closed"
+	statusHidden Status = "hidden"
+)
+
+const (
+	PriorityLow Priority = iota
+	PriorityMedium
+	_
+	PriorityHigh
+	Untyped = 4
+	UntypedToo
+)
+
+const StatusDraft Status = "draft"
+
+const (
+	Level, Other Priority = 5, 6
+)
+
+var StatusVar Status = "var"
+`
+
+type collectTest struct {
+	arg     []string
+	want    []Enum
+	wantErr bool
+}
+
+func TestCollect(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "models.go", testSource, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []collectTest{
+		{[]string{"Status"}, []Enum{{"Status", []string{"StatusOpen", "StatusClosed", "statusHidden", "StatusDraft"}}}, false},
+		{[]string{"Priority", "Status"}, []Enum{
+			{"Priority", []string{"PriorityLow", "PriorityMedium", "PriorityHigh", "Level", "Other"}},
+			{"Status", []string{"StatusOpen", "StatusClosed", "statusHidden", "StatusDraft"}}}, false},
+		{[]string{"Missing"}, nil, true},
+		{[]string{}, []Enum{}, false},
+	}
+
+	for _, test := range tests {
+		have, err := Collect([]*ast.File{file}, test.arg)
+		haveErr := err != nil
+		if !reflect.DeepEqual(have, test.want) || haveErr != test.wantErr {
+			t.Errorf("\nGot: %#v;\nWant: %#v;\nErr: %#v", have, test.want, err)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	want := `// Code generated by schema-enum; DO NOT EDIT.
+
+package models
+
+import "github.com/s-augustovitko/mongo-schema-go/pkg/schema"
+
+func init() {
+	if err := schema.RegisterEnum(PriorityLow, PriorityHigh); err != nil {
+		panic(err)
+	}
+	if err := schema.RegisterEnum(StatusOpen, StatusClosed); err != nil {
+		panic(err)
+	}
+}
+`
+
+	have, err := Generate("models", []Enum{
+		{"Status", []string{"StatusOpen", "StatusClosed"}},
+		{"Priority", []string{"PriorityLow", "PriorityHigh"}},
+	})
+	if string(have) != want || err != nil {
+		t.Errorf("\nGot: %v;\nWant: %v;\nErr: %#v", string(have), want, err)
+	}
+}
+
+type runTest struct {
+	arg1    string
+	arg2    []string
+	want    string
+	wantErr bool
+}
+
+func TestRun(t *testing.T) {
+	tests := []runTest{
+		{"testdata/models", []string{"Status", "Priority"}, `// Code generated by schema-enum; DO NOT EDIT.
+
+package models
+
+import "github.com/s-augustovitko/mongo-schema-go/pkg/schema"
+
+func init() {
+	if err := schema.RegisterEnum(PriorityLow, PriorityMedium, priorityInternal, PriorityHigh); err != nil {
+		panic(err)
+	}
+	if err := schema.RegisterEnum(StatusDraft, StatusOpen, StatusClosed, statusHidden); err != nil {
+		panic(err)
+	}
+}
+`, false},
+		{"testdata/models", []string{"Untyped"}, "", true},
+		{"testdata/missing", []string{"Status"}, "", true},
+	}
+
+	for _, test := range tests {
+		have, err := Run(test.arg1, test.arg2)
+		haveErr := err != nil
+		if string(have) != test.want || haveErr != test.wantErr {
+			t.Errorf("\nGot: %v;\nWant: %v;\nErr: %#v", string(have), test.want, err)
+		}
+	}
+}
@@ -0,0 +1,6 @@
+//go:build ignore
+
+package models
+
+// Excluded by its build constraint, so its constants are not registered
+const StatusLegacy Status = "legacy"
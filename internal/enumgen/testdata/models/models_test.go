@@ -0,0 +1,3 @@
+package models
+
+const StatusTest Status = "test"
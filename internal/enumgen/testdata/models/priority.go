@@ -0,0 +1,15 @@
+package models
+
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityMedium
+	_
+	priorityInternal
+	PriorityHigh
+	Other = 4
+	OtherToo
+)
+
+const StatusDraft Status = "draft"
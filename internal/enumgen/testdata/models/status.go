@@ -0,0 +1,11 @@
+package models
+
+type Status string
+
+const (
+	StatusOpen   Status = "open"
+	StatusClosed Status = "closed"
+	statusHidden Status = "hidden"
+)
+
+const Untyped = "open"
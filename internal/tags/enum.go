@@ -0,0 +1,84 @@
+package tags
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// Value of the enum tag, quoted values are always strings
+type EnumValue struct {
+	Val    string
+	Quoted bool
+}
+
+// Parses the enum tag with the values coerced to the bson types of the field
+// unquoted values are tried as null, bool, int, long and double before string, quotes force a string
+func ParseEnum(tag string, types []string) ([]interface{}, error) {
+	items, err := SplitList(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]EnumValue, 0, len(items))
+	for _, item := range items {
+		values = append(values, EnumValue{Val: item.Val, Quoted: item.Quoted})
+	}
+	return coerceEnumValues(values, types)
+}
+
+// Gets the enum values registered for typ coerced to the bson types of the field
+func GetEnum(typ reflect.Type, types []string) ([]interface{}, error) {
+	values, _ := lookupEnum(typ)
+	return coerceEnumValues(values, types)
+}
+
+// Coerces every value to the bson types of the field
+func coerceEnumValues(values []EnumValue, types []string) ([]interface{}, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	out := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		val, ok := coerceEnum(value, types)
+		if !ok {
+			return nil, fmt.Errorf("enum value [%v] does not match the types %v", value.Val, types)
+		}
+		out = append(out, val)
+	}
+	return out, nil
+}
+
+// Coerces the value to the first bson type that can represent it
+func coerceEnum(value EnumValue, types []string) (interface{}, bool) {
+	if value.Quoted {
+		return value.Val, Contains(types, "string")
+	}
+
+	if value.Val == "null" && Contains(types, "null") {
+		return nil, true
+	}
+	if Contains(types, "bool") && (value.Val == "true" || value.Val == "false") {
+		return value.Val == "true", true
+	}
+	if Contains(types, "int") || Contains(types, "long") {
+		if val, err := strconv.ParseInt(value.Val, 10, 64); err == nil {
+			// The driver writes the values that fit in 32 bits as "int"
+			if Contains(types, "int") && val >= math.MinInt32 && val <= math.MaxInt32 {
+				return int32(val), true
+			}
+			if Contains(types, "long") {
+				return val, true
+			}
+		}
+	}
+	if Contains(types, "double") || Contains(types, "decimal") {
+		if val, err := strconv.ParseFloat(value.Val, 64); err == nil {
+			return val, true
+		}
+	}
+
+	return value.Val, Contains(types, "string")
+}
@@ -0,0 +1,46 @@
+package tags
+
+import (
+	"reflect"
+	"testing"
+)
+
+type parseEnumTest struct {
+	arg1    string
+	arg2    []string
+	want    []interface{}
+	wantErr bool
+}
+
+func TestParseEnum(t *testing.T) {
+	tests := []parseEnumTest{
+		{"", []string{"string"}, nil, false},
+		{"a,b", []string{"string"}, []interface{}{"a", "b"}, false},
+		{"1,2", []string{"string"}, []interface{}{"1", "2"}, false},
+		{"1,-2", []string{"int"}, []interface{}{int32(1), int32(-2)}, false},
+		{"1,5000000000", []string{"int", "long"}, []interface{}{int32(1), int64(5000000000)}, false},
+		{"5000000000", []string{"int"}, nil, true},
+		{"1", []string{"long"}, []interface{}{int64(1)}, false},
+		{"1,1.5", []string{"double"}, []interface{}{float64(1), 1.5}, false},
+		{"1,1.5", []string{"int", "double"}, []interface{}{int32(1), 1.5}, false},
+		{"1.5", []string{"decimal"}, []interface{}{1.5}, false},
+		{"1.5", []string{"int"}, nil, true},
+		{"true,false", []string{"bool"}, []interface{}{true, false}, false},
+		{"True", []string{"bool"}, nil, true},
+		{"null,a", []string{"string", "null"}, []interface{}{nil, "a"}, false},
+		{"null", []string{"string"}, []interface{}{"null"}, false},
+		{"null", []string{"int"}, nil, true},
+		{"'1',1", []string{"string", "int"}, []interface{}{"1", int32(1)}, false},
+		{"'1'", []string{"int"}, nil, true},
+		{"a", []string{"objectId"}, nil, true},
+		{"'a", []string{"string"}, nil, true},
+	}
+
+	for _, test := range tests {
+		have, err := ParseEnum(test.arg1, test.arg2)
+		haveErr := err != nil
+		if !reflect.DeepEqual(have, test.want) || haveErr != test.wantErr {
+			t.Errorf("\nGot: %#v;\nWant: %#v;\nErr: %#v", have, test.want, err)
+		}
+	}
+}
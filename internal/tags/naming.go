@@ -0,0 +1,50 @@
+package tags
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Policy used to name the fields that do not have a name in their tags
+type NamingMode int
+
+const (
+	// The first character is lower cased, eg. "UserID" is "userID" (default)
+	NamingLowerFirst NamingMode = iota
+	// Every character is lower cased like the go driver does, eg. "UserID" is "userid"
+	NamingLowerCase
+	// Words are lower cased and separated by underscores, eg. "UserID" is "user_id"
+	NamingSnakeCase
+	// The name of the go field, eg. "UserID" is "UserID"
+	NamingAsIs
+)
+
+// Gets the name of a field following the policy
+func FieldName(name string, policy NamingMode) string {
+	switch policy {
+	case NamingLowerCase:
+		return strings.ToLower(name)
+	case NamingSnakeCase:
+		return snakeCase(name)
+	case NamingAsIs:
+		return name
+	}
+	return firstCharLower(name)
+}
+
+// Converts a name to snake case, acronyms are a single word (eg. "HTTPServer" is "http_server")
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var out strings.Builder
+	for i, char := range runes {
+		if unicode.IsUpper(char) && i > 0 {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				out.WriteRune('_')
+			}
+		}
+		out.WriteRune(unicode.ToLower(char))
+	}
+	return out.String()
+}
@@ -0,0 +1,29 @@
+package tags
+
+import "testing"
+
+type fieldNameTest struct {
+	arg1 string
+	arg2 NamingMode
+	want string
+}
+
+func TestFieldName(t *testing.T) {
+	tests := []fieldNameTest{
+		{"UserID", NamingLowerFirst, "userID"},
+		{"UserID", NamingLowerCase, "userid"},
+		{"UserID", NamingSnakeCase, "user_id"},
+		{"UserID", NamingAsIs, "UserID"},
+		{"HTTPServer", NamingSnakeCase, "http_server"},
+		{"ID", NamingSnakeCase, "id"},
+		{"Address2Line", NamingSnakeCase, "address2_line"},
+		{"name", NamingSnakeCase, "name"},
+		{"CreatedAt", NamingSnakeCase, "created_at"},
+	}
+
+	for _, test := range tests {
+		if have := FieldName(test.arg1, test.arg2); have != test.want {
+			t.Errorf("\nGot: %#v;\nWant: %#v", have, test.want)
+		}
+	}
+}
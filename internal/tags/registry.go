@@ -0,0 +1,142 @@
+package tags
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// Types registered by the users of the package, they take precedence over any other type mapping
+// Enums are the values allowed for every field of the registered type
+var registry = struct {
+	sync.RWMutex
+	types map[reflect.Type][]string
+	names map[string][]string
+	enums map[reflect.Type][]EnumValue
+}{
+	types: map[reflect.Type][]string{},
+	names: map[string][]string{},
+	enums: map[reflect.Type][]EnumValue{},
+}
+
+// Registers the bson types used for typ, pointers are resolved to the type they point to
+func RegisterType(typ reflect.Type, bsonTypes ...string) error {
+	if typ == nil {
+		return fmt.Errorf("can not register a nil type")
+	}
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	types, err := checkRegisterTypes(bsonTypes)
+	if err != nil {
+		return fmt.Errorf("[%v]: %v", typ, err)
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+	registry.types[typ] = types
+	return nil
+}
+
+// Registers the bson types used for the type with the package path and name
+// this allows registering types without importing their package (eg. "go.mongodb.org/mongo-driver/bson/primitive", "ObjectID")
+func RegisterTypeName(pkgPath, name string, bsonTypes ...string) error {
+	if name == "" {
+		return fmt.Errorf("can not register a type without a name")
+	}
+
+	types, err := checkRegisterTypes(bsonTypes)
+	if err != nil {
+		return fmt.Errorf("[%v.%v]: %v", pkgPath, name, err)
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+	registry.names[typeKey(pkgPath, name)] = types
+	return nil
+}
+
+// Gets the registered bson types of typ, types registered directly are checked before names
+func lookupRegistered(typ reflect.Type) ([]string, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	if types, ok := registry.types[typ]; ok {
+		return types, true
+	}
+	if typ.Name() == "" {
+		return nil, false
+	}
+
+	types, ok := registry.names[typeKey(typ.PkgPath(), typ.Name())]
+	return types, ok
+}
+
+// Registers the values allowed for typ, they are used as the enum of the fields of typ without an enum tag
+// values must be of kind string, bool, int, uint or float, string values are always described as strings
+func RegisterEnum(typ reflect.Type, values ...reflect.Value) error {
+	if typ == nil {
+		return fmt.Errorf("can not register a nil type")
+	}
+	if len(values) == 0 {
+		return fmt.Errorf("[%v]: at least one enum value is required", typ)
+	}
+
+	enum := make([]EnumValue, 0, len(values))
+	for _, val := range values {
+		value, err := enumValue(val)
+		if err != nil {
+			return fmt.Errorf("[%v]: %v", typ, err)
+		}
+		enum = append(enum, value)
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+	registry.enums[typ] = enum
+	return nil
+}
+
+// Gets the enum values registered for typ
+func lookupEnum(typ reflect.Type) ([]EnumValue, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	enum, ok := registry.enums[typ]
+	return enum, ok
+}
+
+// Formats the value as it would be written in the enum tag
+func enumValue(val reflect.Value) (EnumValue, error) {
+	switch val.Kind() {
+	case reflect.String:
+		return EnumValue{Val: val.String(), Quoted: true}, nil
+	case reflect.Bool:
+		return EnumValue{Val: strconv.FormatBool(val.Bool())}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return EnumValue{Val: strconv.FormatInt(val.Int(), 10)}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return EnumValue{Val: strconv.FormatUint(val.Uint(), 10)}, nil
+	case reflect.Float32, reflect.Float64:
+		return EnumValue{Val: strconv.FormatFloat(val.Float(), 'g', -1, 64)}, nil
+	}
+	return EnumValue{}, fmt.Errorf("enum values of kind [%v] are not supported", val.Kind())
+}
+
+// Checks that at least one bson type is sent and all of them are valid
+func checkRegisterTypes(bsonTypes []string) ([]string, error) {
+	types, err := checkValidTypeArr(bsonTypes)
+	if err != nil {
+		return types, err
+	}
+	if len(types) == 0 {
+		return types, fmt.Errorf("at least one bson type is required")
+	}
+	return types, nil
+}
+
+func typeKey(pkgPath, name string) string {
+	return pkgPath + "." + name
+}
@@ -0,0 +1,153 @@
+package tags
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testRegisteredID [12]byte
+
+type testRegisteredName struct{}
+
+type registerTypeTest struct {
+	arg1    reflect.Type
+	arg2    []string
+	haveErr bool
+}
+
+func TestRegisterType(t *testing.T) {
+	tests := []registerTypeTest{
+		{reflect.TypeOf(testRegisteredID{}), []string{"objectId"}, false},
+		{reflect.TypeOf(&testRegisteredID{}), []string{"objectId"}, false},
+		{reflect.TypeOf(testRegisteredID{}), []string{}, true},
+		{reflect.TypeOf(testRegisteredID{}), []string{"invalid"}, true},
+		{nil, []string{"objectId"}, true},
+	}
+
+	for _, test := range tests {
+		err := RegisterType(test.arg1, test.arg2...)
+
+		if haveErr := err != nil; haveErr != test.haveErr {
+			t.Errorf("\nArg: %v, %#v;\nErr: %#v", test.arg1, test.arg2, err)
+		}
+	}
+}
+
+type registerTypeNameTest struct {
+	arg1, arg2 string
+	arg3       []string
+	haveErr    bool
+}
+
+func TestRegisterTypeName(t *testing.T) {
+	pkgPath := reflect.TypeOf(testRegisteredName{}).PkgPath()
+	tests := []registerTypeNameTest{
+		{pkgPath, "testRegisteredName", []string{"decimal", "string"}, false},
+		{pkgPath, "", []string{"decimal"}, true},
+		{pkgPath, "testRegisteredName", nil, true},
+		{pkgPath, "testRegisteredName", []string{"double", "invalid"}, true},
+	}
+
+	for _, test := range tests {
+		err := RegisterTypeName(test.arg1, test.arg2, test.arg3...)
+
+		if haveErr := err != nil; haveErr != test.haveErr {
+			t.Errorf("\nArg: %v, %v, %#v;\nErr: %#v", test.arg1, test.arg2, test.arg3, err)
+		}
+	}
+}
+
+type lookupRegisteredTest struct {
+	arg    reflect.Type
+	want   []string
+	wantOk bool
+}
+
+func TestLookupRegistered(t *testing.T) {
+	if err := RegisterType(reflect.TypeOf(testRegisteredID{}), "objectId"); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterTypeName(reflect.TypeOf(testRegisteredName{}).PkgPath(), "testRegisteredName", "decimal"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []lookupRegisteredTest{
+		{reflect.TypeOf(testRegisteredID{}), []string{"objectId"}, true},
+		{reflect.TypeOf(testRegisteredName{}), []string{"decimal"}, true},
+		{reflect.TypeOf([12]byte{}), nil, false},
+		{reflect.TypeOf(struct{}{}), nil, false},
+	}
+
+	for _, test := range tests {
+		have, ok := lookupRegistered(test.arg)
+
+		if !CompareArr(have, test.want) || ok != test.wantOk {
+			t.Errorf("\nGot: %#v, %#v;\nWant: %#v, %#v", have, ok, test.want, test.wantOk)
+		}
+	}
+
+	have, err := GetType("", reflect.TypeOf(testRegisteredID{}), NumericStrict)
+	if !CompareArr(have, []string{"objectId"}) || err != nil {
+		t.Errorf("\nGot: %#v;\nWant: %#v;\nErr: %#v", have, []string{"objectId"}, err)
+	}
+}
+
+type testRegisteredEnum string
+
+type registerEnumTest struct {
+	arg1    reflect.Type
+	arg2    []reflect.Value
+	haveErr bool
+}
+
+func TestRegisterEnum(t *testing.T) {
+	enumType := reflect.TypeOf(testRegisteredEnum(""))
+	tests := []registerEnumTest{
+		{enumType, []reflect.Value{reflect.ValueOf(testRegisteredEnum("a")), reflect.ValueOf(testRegisteredEnum("b, c"))}, false},
+		{enumType, nil, true},
+		{nil, []reflect.Value{reflect.ValueOf("a")}, true},
+		{reflect.TypeOf(struct{}{}), []reflect.Value{reflect.ValueOf(struct{}{})}, true},
+	}
+
+	for _, test := range tests {
+		err := RegisterEnum(test.arg1, test.arg2...)
+
+		if haveErr := err != nil; haveErr != test.haveErr {
+			t.Errorf("\nArg: %v, %#v;\nErr: %#v", test.arg1, test.arg2, err)
+		}
+	}
+}
+
+type getEnumTest struct {
+	arg1    reflect.Value
+	arg2    []string
+	want    []interface{}
+	wantErr bool
+}
+
+func TestGetEnum(t *testing.T) {
+	tests := []getEnumTest{
+		{reflect.ValueOf(testRegisteredEnum("b, c")), []string{"string"}, []interface{}{"b, c"}, false},
+		{reflect.ValueOf(testRegisteredEnum("1")), []string{"int"}, nil, true},
+		{reflect.ValueOf(int8(-3)), []string{"int"}, []interface{}{int32(-3)}, false},
+		{reflect.ValueOf(uint64(7)), []string{"long"}, []interface{}{int64(7)}, false},
+		{reflect.ValueOf(float32(0.5)), []string{"double"}, []interface{}{0.5}, false},
+		{reflect.ValueOf(true), []string{"bool"}, []interface{}{true}, false},
+	}
+
+	for _, test := range tests {
+		if err := RegisterEnum(test.arg1.Type(), test.arg1); err != nil {
+			t.Fatal(err)
+		}
+		have, err := GetEnum(test.arg1.Type(), test.arg2)
+		haveErr := err != nil
+		if !reflect.DeepEqual(have, test.want) || haveErr != test.wantErr {
+			t.Errorf("\nGot: %#v;\nWant: %#v;\nErr: %#v", have, test.want, err)
+		}
+	}
+
+	have, err := GetEnum(reflect.TypeOf(struct{}{}), []string{"object"})
+	if have != nil || err != nil {
+		t.Errorf("\nGot: %#v;\nWant: %#v;\nErr: %#v", have, nil, err)
+	}
+}
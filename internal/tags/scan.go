@@ -0,0 +1,194 @@
+package tags
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error in the syntax of a tag, offset is the position of the character (in bytes) where the error was found
+type SyntaxError struct {
+	Tag    string
+	Offset int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	if e.Tag == "" {
+		return fmt.Sprintf("invalid tag at offset %v: %v", e.Offset, e.Msg)
+	}
+	return fmt.Sprintf("invalid [%v] tag at offset %v: %v", e.Tag, e.Offset, e.Msg)
+}
+
+// Sets the name of the tag of syntax errors
+func WithTagName(name string, err error) error {
+	if syntaxErr, ok := err.(*SyntaxError); ok && syntaxErr.Tag == "" {
+		syntaxErr.Tag = name
+	}
+	return err
+}
+
+func syntaxError(offset int, format string, args ...interface{}) *SyntaxError {
+	return &SyntaxError{Offset: offset, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Item of a comma separated tag, eg. "min=1" or "required" in the validation tag or "a" in the enum tag
+// Offset is the position of the item in the tag
+type TagItem struct {
+	Key    string
+	Val    string
+	HasVal bool
+	Quoted bool
+	Offset int
+}
+
+// Splits a comma separated list of values (eg. the enum or type tags)
+// values in single quotes can contain commas and spaces, \' and \\ are escapes inside the quotes
+// outside of the quotes \, is a comma that does not split the values, empty values are ignored
+func SplitList(tag string) ([]TagItem, error) {
+	return scanTag(tag, false, false)
+}
+
+// Splits a comma separated list of values like SplitList, but commas inside (), [] and {} do not split unquoted values
+// used by tags that can contain json values (eg. "{"required": ["a", "b"]},name")
+func SplitNested(tag string) ([]TagItem, error) {
+	return scanTag(tag, false, true)
+}
+
+// Splits a comma separated list of keys with optional values (eg. "required,min=1,pattern=^[a-z]{1,5}$")
+// values follow the rules of SplitList, also commas inside (), [] and {} do not split unquoted values
+// so regular expressions can be used without quotes in most cases
+func SplitKeyValues(tag string) ([]TagItem, error) {
+	return scanTag(tag, true, true)
+}
+
+func scanTag(tag string, keyValues, brackets bool) ([]TagItem, error) {
+	out := []TagItem{}
+	s := scanner{tag: tag}
+
+	for !s.done() {
+		s.skipSpaces()
+		item := TagItem{Offset: s.pos}
+
+		if keyValues {
+			start := s.pos
+			for !s.done() && s.peek() != '=' && s.peek() != ',' {
+				s.pos++
+			}
+			item.Key = strings.TrimSpace(tag[start:s.pos])
+			if !s.done() && s.peek() == '=' {
+				if item.Key == "" {
+					return out, syntaxError(s.pos, "missing key before [=]")
+				}
+				s.pos++
+				item.HasVal = true
+			}
+		}
+
+		if !keyValues || item.HasVal {
+			var err error
+			item.Val, item.Quoted, err = s.value(brackets)
+			if err != nil {
+				return out, err
+			}
+		}
+
+		if !s.done() {
+			s.pos++ // Comma
+		}
+		if item.Key != "" || item.Val != "" || item.Quoted || item.HasVal {
+			out = append(out, item)
+		}
+	}
+
+	return out, nil
+}
+
+var closingBrackets = map[byte]byte{'(': ')', '[': ']', '{': '}'}
+
+type scanner struct {
+	tag string
+	pos int
+}
+
+func (s *scanner) done() bool {
+	return s.pos >= len(s.tag)
+}
+
+func (s *scanner) peek() byte {
+	return s.tag[s.pos]
+}
+
+func (s *scanner) skipSpaces() {
+	for !s.done() && s.peek() == ' ' {
+		s.pos++
+	}
+}
+
+// Reads a value up to the next comma that splits the items
+func (s *scanner) value(brackets bool) (string, bool, error) {
+	s.skipSpaces()
+	if !s.done() && s.peek() == '\'' {
+		val, err := s.quoted()
+		return val, true, err
+	}
+
+	var val strings.Builder
+	closing := []byte{}
+	openings := []int{}
+	for ; !s.done(); s.pos++ {
+		char := s.peek()
+		if char == '\\' && s.pos+1 < len(s.tag) {
+			s.pos++
+			// Only commas are escaped, other escapes are kept (eg. regular expressions)
+			if s.peek() != ',' {
+				val.WriteByte(char)
+			}
+			val.WriteByte(s.peek())
+			continue
+		}
+		if char == ',' && len(closing) == 0 {
+			break
+		}
+
+		if brackets {
+			switch char {
+			case '(', '[', '{':
+				closing = append(closing, closingBrackets[char])
+				openings = append(openings, s.pos)
+			case ')', ']', '}':
+				if len(closing) > 0 && closing[len(closing)-1] == char {
+					closing = closing[:len(closing)-1]
+					openings = openings[:len(openings)-1]
+				}
+			}
+		}
+		val.WriteByte(char)
+	}
+
+	if len(closing) > 0 {
+		return "", false, syntaxError(openings[len(openings)-1], "unclosed [%c], quote the value if it is intended", s.tag[openings[len(openings)-1]])
+	}
+	return strings.TrimSpace(val.String()), false, nil
+}
+
+// Reads a value in single quotes, the scanner must be at the opening quote
+func (s *scanner) quoted() (string, error) {
+	var val strings.Builder
+	start := s.pos
+	for s.pos++; !s.done() && s.peek() != '\''; s.pos++ {
+		if s.peek() == '\\' && s.pos+1 < len(s.tag) && (s.tag[s.pos+1] == '\'' || s.tag[s.pos+1] == '\\') {
+			s.pos++
+		}
+		val.WriteByte(s.peek())
+	}
+	if s.done() {
+		return "", syntaxError(start, "unterminated quote")
+	}
+
+	s.pos++
+	s.skipSpaces()
+	if !s.done() && s.peek() != ',' {
+		return "", syntaxError(s.pos, "unexpected character [%c] after a quoted value, expected a comma", s.peek())
+	}
+	return val.String(), nil
+}
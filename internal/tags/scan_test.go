@@ -0,0 +1,128 @@
+package tags
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type splitListTest struct {
+	arg     string
+	want    []TagItem
+	wantErr string
+}
+
+func TestSplitList(t *testing.T) {
+	tests := []splitListTest{
+		{"", []TagItem{}, ""},
+		{"  ", []TagItem{}, ""},
+		{"a", []TagItem{{Val: "a"}}, ""},
+		{"a, b ,c", []TagItem{{Val: "a"}, {Val: "b", Offset: 3}, {Val: "c", Offset: 6}}, ""},
+		{"a,,b,", []TagItem{{Val: "a"}, {Val: "b", Offset: 3}}, ""},
+		{"a=b,(c", []TagItem{{Val: "a=b"}, {Val: "(c", Offset: 4}}, ""},
+		{`a\,b,c`, []TagItem{{Val: "a,b"}, {Val: "c", Offset: 5}}, ""},
+		{`a\b`, []TagItem{{Val: `a\b`}}, ""},
+		{"'a, b', c", []TagItem{{Val: "a, b", Quoted: true}, {Val: "c", Offset: 8}}, ""},
+		{"'', ' x '", []TagItem{{Val: "", Quoted: true}, {Val: " x ", Quoted: true, Offset: 4}}, ""},
+		{`'it\'s','a\\b','a\b'`, []TagItem{{Val: "it's", Quoted: true}, {Val: `a\b`, Quoted: true, Offset: 8}, {Val: `a\b`, Quoted: true, Offset: 15}}, ""},
+		{"'a", []TagItem{}, "invalid tag at offset 0: unterminated quote"},
+		{"b, 'a", []TagItem{{Val: "b"}}, "invalid tag at offset 3: unterminated quote"},
+		{"'a' b", []TagItem{}, "invalid tag at offset 4: unexpected character [b] after a quoted value, expected a comma"},
+		{`a,'b\'`, []TagItem{{Val: "a"}}, "invalid tag at offset 2: unterminated quote"},
+	}
+
+	for _, test := range tests {
+		have, err := SplitList(test.arg)
+		haveErr := ""
+		if err != nil {
+			haveErr = err.Error()
+		}
+		if !reflect.DeepEqual(have, test.want) || haveErr != test.wantErr {
+			t.Errorf("\nGot: %#v;\nWant: %#v;\nErr: %#v", have, test.want, err)
+		}
+	}
+}
+
+type splitNestedTest struct {
+	arg     string
+	want    []TagItem
+	wantErr bool
+}
+
+func TestSplitNested(t *testing.T) {
+	tests := []splitNestedTest{
+		{"", []TagItem{}, false},
+		{"a, b", []TagItem{{Val: "a"}, {Val: "b", Offset: 3}}, false},
+		{`{"required": ["a", "b"]},name`, []TagItem{{Val: `{"required": ["a", "b"]}`}, {Val: "name", Offset: 25}}, false},
+		{"'{', (a,b)", []TagItem{{Val: "{", Quoted: true}, {Val: "(a,b)", Offset: 5}}, false},
+		{`{"a": 1`, []TagItem{}, true},
+	}
+
+	for _, test := range tests {
+		have, err := SplitNested(test.arg)
+		haveErr := err != nil
+		if !reflect.DeepEqual(have, test.want) || haveErr != test.wantErr {
+			t.Errorf("\nGot: %#v;\nWant: %#v;\nErr: %#v", have, test.want, err)
+		}
+	}
+}
+
+type splitKeyValuesTest struct {
+	arg     string
+	want    []TagItem
+	wantErr string
+}
+
+func TestSplitKeyValues(t *testing.T) {
+	tests := []splitKeyValuesTest{
+		{"", []TagItem{}, ""},
+		{"required", []TagItem{{Key: "required"}}, ""},
+		{"required, min=1", []TagItem{{Key: "required"}, {Key: "min", Val: "1", HasVal: true, Offset: 10}}, ""},
+		{"min = 1 ,max=", []TagItem{{Key: "min", Val: "1", HasVal: true}, {Key: "max", HasVal: true, Offset: 9}}, ""},
+		{"pattern=^[a-z]{1,5}$,min=1", []TagItem{{Key: "pattern", Val: "^[a-z]{1,5}$", HasVal: true}, {Key: "min", Val: "1", HasVal: true, Offset: 21}}, ""},
+		{"pattern=^(a|b,c)=d$", []TagItem{{Key: "pattern", Val: "^(a|b,c)=d$", HasVal: true}}, ""},
+		{`pattern=^\[a,b`, []TagItem{{Key: "pattern", Val: `^\[a`, HasVal: true}, {Key: "b", Offset: 13}}, ""},
+		{`pattern=a\,b`, []TagItem{{Key: "pattern", Val: "a,b", HasVal: true}}, ""},
+		{`pattern=\d+\.\d+`, []TagItem{{Key: "pattern", Val: `\d+\.\d+`, HasVal: true}}, ""},
+		{"pattern=a)b", []TagItem{{Key: "pattern", Val: "a)b", HasVal: true}}, ""},
+		{"pattern='^[a-z,]+$', required", []TagItem{{Key: "pattern", Val: "^[a-z,]+$", HasVal: true, Quoted: true}, {Key: "required", Offset: 21}}, ""},
+		{`pattern='it\'s'`, []TagItem{{Key: "pattern", Val: "it's", HasVal: true, Quoted: true}}, ""},
+		{"pattern=''", []TagItem{{Key: "pattern", HasVal: true, Quoted: true}}, ""},
+		{"min=1,pattern=^[a-z", []TagItem{{Key: "min", Val: "1", HasVal: true}}, "invalid tag at offset 15: unclosed [[], quote the value if it is intended"},
+		{"pattern='^[a-z", []TagItem{}, "invalid tag at offset 8: unterminated quote"},
+		{"pattern='a'b", []TagItem{}, "invalid tag at offset 11: unexpected character [b] after a quoted value, expected a comma"},
+		{"min=1, =2", []TagItem{{Key: "min", Val: "1", HasVal: true}}, "invalid tag at offset 7: missing key before [=]"},
+	}
+
+	for _, test := range tests {
+		have, err := SplitKeyValues(test.arg)
+		haveErr := ""
+		if err != nil {
+			haveErr = err.Error()
+		}
+		if !reflect.DeepEqual(have, test.want) || haveErr != test.wantErr {
+			t.Errorf("\nGot: %#v;\nWant: %#v;\nErr: %#v", have, test.want, err)
+		}
+	}
+}
+
+type withTagNameTest struct {
+	arg1 string
+	arg2 error
+	want string
+}
+
+func TestWithTagName(t *testing.T) {
+	tests := []withTagNameTest{
+		{"validation", &SyntaxError{Offset: 3, Msg: "unterminated quote"}, "invalid [validation] tag at offset 3: unterminated quote"},
+		{"validation", &SyntaxError{Tag: "items", Offset: 3, Msg: "unterminated quote"}, "invalid [items] tag at offset 3: unterminated quote"},
+		{"validation", errors.New("other error"), "other error"},
+	}
+
+	for _, test := range tests {
+		have := WithTagName(test.arg1, test.arg2).Error()
+		if have != test.want {
+			t.Errorf("\nGot: %#v;\nWant: %#v", have, test.want)
+		}
+	}
+}
@@ -7,13 +7,17 @@ import (
 
 // Get the tag and inline values
 // first non empty tag value is retrieved
-// only fieldTag is being checked for inline value (fieldTag: "tag,inline")
+// inline can be set in the fieldTag or the bsonTag (fieldTag: "tag,inline" || bsonTag: ",inline")
 func GetTag(fieldTag, bsonTag, name string) (string, bool) {
-	fieldArr := SplitTrim(fieldTag, ",")
-	n := len(fieldArr)
+	return GetTagWithPolicy(fieldTag, bsonTag, name, NamingLowerFirst)
+}
+
+// Get the tag and inline values like GetTag, the name of fields without a name in their tags follows the policy
+func GetTagWithPolicy(fieldTag, bsonTag, name string, policy NamingMode) (string, bool) {
+	isInline := HasOption(fieldTag, "inline") || HasOption(bsonTag, "inline")
 
-	isInline := n > 1 && strings.ToLower(fieldArr[1]) == "inline"
-	if n > 0 && fieldArr[0] != "" {
+	fieldArr := SplitTrim(fieldTag, ",")
+	if len(fieldArr) > 0 && fieldArr[0] != "" {
 		return fieldArr[0], isInline
 	}
 
@@ -22,7 +26,30 @@ func GetTag(fieldTag, bsonTag, name string) (string, bool) {
 		return bsonArr[0], isInline
 	}
 
-	return firstCharLower(name), isInline
+	return FieldName(name, policy), isInline
+}
+
+// Checks if the name of the field is set in the fieldTag or the bsonTag
+func HasName(fieldTag, bsonTag string) bool {
+	fieldArr := SplitTrim(fieldTag, ",")
+	bsonArr := SplitTrim(bsonTag, ",")
+	return (len(fieldArr) > 0 && fieldArr[0] != "") || (len(bsonArr) > 0 && bsonArr[0] != "")
+}
+
+// Checks if the field is skipped with "-" in the fieldTag or the bsonTag, like the go driver does
+func IsSkipped(fieldTag, bsonTag string) bool {
+	return strings.TrimSpace(fieldTag) == "-" || strings.TrimSpace(bsonTag) == "-"
+}
+
+// Checks if the tag options (values after the name) contain option (eg. HasOption("name,omitempty", "omitempty"))
+func HasOption(tag, option string) bool {
+	options := SplitTrim(tag, ",")
+	for i := 1; i < len(options); i++ {
+		if strings.ToLower(options[i]) == strings.ToLower(option) {
+			return true
+		}
+	}
+	return false
 }
 
 // Converts the first character of a string to lower case
@@ -31,3 +58,50 @@ func firstCharLower(name string) string {
 	a[0] = unicode.ToLower(a[0])
 	return string(a)
 }
+
+// Gets the keys of a struct tag in order, following the conventional format of reflect.StructTag
+// parsing stops at the first malformed key or value
+func Keys(tag string) []string {
+	keys, _ := StructTagKeys(tag)
+	return keys
+}
+
+// Gets the keys of a struct tag in order like Keys, returns an error with the keys before it if the tag is malformed
+func StructTagKeys(tag string) ([]string, error) {
+	keys := []string{}
+	offset := 0
+	for tag != "" {
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag, offset = tag[i:], offset+i
+		if tag == "" {
+			break
+		}
+
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			return keys, syntaxError(offset, "expected a key followed by a quoted value (eg. key:\"value\")")
+		}
+		name := tag[:i]
+		tag, offset = tag[i+1:], offset+i+1
+
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			return keys, syntaxError(offset, "unterminated quote")
+		}
+		keys = append(keys, name)
+		tag, offset = tag[i+1:], offset+i+1
+	}
+	return keys, nil
+}
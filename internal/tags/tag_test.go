@@ -38,6 +38,10 @@ func TestGetTag(t *testing.T) {
 		{" name ,    ", "", "Name3", "name", false},
 		{"name,", "name2", "Name3", "name", false},
 		{"Name123", "name2", "Name3", "Name123", false},
+		{"", "name,inline", "Name3", "name", true},
+		{"", ",omitempty,inline", "Name3", "name3", true},
+		{"name", ",inline", "Name3", "name", true},
+		{"", "name,omitempty", "Name3", "name", false},
 	}
 
 	for _, test := range tests {
@@ -48,3 +52,116 @@ func TestGetTag(t *testing.T) {
 		}
 	}
 }
+
+type hasOptionTest struct {
+	arg1, arg2 string
+	want       bool
+}
+
+func TestHasOption(t *testing.T) {
+	tests := []hasOptionTest{
+		{"", "omitempty", false},
+		{"omitempty", "omitempty", false},
+		{"name,omitempty", "omitempty", true},
+		{" , inline , omitempty ", "omitempty", true},
+		{",OmitEmpty", "omitempty", true},
+		{"name,inline", "omitempty", false},
+	}
+
+	for _, test := range tests {
+		if have := HasOption(test.arg1, test.arg2); have != test.want {
+			t.Errorf("\nGot: %#v;\nWant: %#v;\nTest: %#v", have, test.want, test)
+		}
+	}
+}
+
+type isSkippedTest struct {
+	arg1, arg2 string
+	want       bool
+}
+
+func TestIsSkipped(t *testing.T) {
+	tests := []isSkippedTest{
+		{"", "", false},
+		{"-", "", true},
+		{"", "-", true},
+		{" - ", "name", true},
+		{"name", "-", true},
+		{"-,inline", "", false},
+		{"", "-,omitempty", false},
+	}
+
+	for _, test := range tests {
+		if have := IsSkipped(test.arg1, test.arg2); have != test.want {
+			t.Errorf("\nGot: %#v;\nWant: %#v;\nTest: %#v", have, test.want, test)
+		}
+	}
+}
+
+type hasNameTest struct {
+	arg1, arg2 string
+	want       bool
+}
+
+func TestHasName(t *testing.T) {
+	tests := []hasNameTest{
+		{"", "", false},
+		{",inline", " ,omitempty", false},
+		{"name", "", true},
+		{"", "name,inline", true},
+		{" , inline", "name", true},
+	}
+
+	for _, test := range tests {
+		if have := HasName(test.arg1, test.arg2); have != test.want {
+			t.Errorf("\nGot: %#v;\nWant: %#v;\nTest: %#v", have, test.want, test)
+		}
+	}
+}
+
+type keysTest struct {
+	arg  string
+	want []string
+}
+
+func TestKeys(t *testing.T) {
+	tests := []keysTest{
+		{``, []string{}},
+		{`bson:"name"`, []string{"bson"}},
+		{`bson:"name" items.2:"min=1"  itemsType.3:"int"`, []string{"bson", "items.2", "itemsType.3"}},
+		{`validation:"pattern=\"a\"" enum:"a,b"`, []string{"validation", "enum"}},
+		{`bson:"name" invalid enum:"a"`, []string{"bson"}},
+		{`bson:"name`, []string{}},
+	}
+
+	for _, test := range tests {
+		if have := Keys(test.arg); !CompareArr(have, test.want) {
+			t.Errorf("\nGot: %#v;\nWant: %#v", have, test.want)
+		}
+	}
+}
+
+type structTagKeysTest struct {
+	arg     string
+	want    []string
+	wantErr string
+}
+
+func TestStructTagKeys(t *testing.T) {
+	tests := []structTagKeysTest{
+		{`bson:"name" items.2:"min=1"`, []string{"bson", "items.2"}, ""},
+		{`bson:"name" invalid`, []string{"bson"}, `invalid tag at offset 12: expected a key followed by a quoted value (eg. key:"value")`},
+		{`bson:"name" enum:"a`, []string{"bson"}, "invalid tag at offset 17: unterminated quote"},
+	}
+
+	for _, test := range tests {
+		have, err := StructTagKeys(test.arg)
+		haveErr := ""
+		if err != nil {
+			haveErr = err.Error()
+		}
+		if !CompareArr(have, test.want) || haveErr != test.wantErr {
+			t.Errorf("\nGot: %#v, %#v;\nWant: %#v, %#v", have, haveErr, test.want, test.wantErr)
+		}
+	}
+}
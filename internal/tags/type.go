@@ -1,8 +1,14 @@
 package tags
 
 import (
+	"encoding/json"
 	"fmt"
+	"math/big"
+	"net"
+	"net/url"
 	"reflect"
+	"regexp"
+	"time"
 )
 
 var validBsonTypes = map[string]bool{
@@ -30,36 +36,125 @@ var validBsonTypes = map[string]bool{
 }
 
 var bsonMap = map[reflect.Kind]string{
-	reflect.Int:       "int",
-	reflect.Int8:      "int",
-	reflect.Int32:     "int",
-	reflect.Int64:     "long",
-	reflect.Float32:   "double",
-	reflect.Float64:   "decimal",
-	reflect.String:    "string",
-	reflect.Bool:      "bool",
-	reflect.Array:     "array",
-	reflect.Slice:     "array",
-	reflect.Struct:    "object",
-	reflect.Map:       "object",
-	reflect.Interface: "objectId",
+	reflect.String: "string",
+	reflect.Bool:   "bool",
+	reflect.Array:  "array",
+	reflect.Slice:  "array",
+	reflect.Struct: "object",
+	reflect.Map:    "object",
 }
 
-// Get type from type tag or reflect.Kind
-func GetType(typeTag string, kind reflect.Kind) ([]string, error) {
-	typeArr, err := checkValidTypeArr(SplitTrim(typeTag, ","))
+// Policy used to map the numeric kinds to bson types
+type NumericMode int
+
+const (
+	// Numeric kinds have the bson types the go driver writes for them
+	// int can be written as "int" or "long" depending on its value
+	NumericStrict NumericMode = iota
+	// Integer kinds accept both "int" and "long", and float kinds also accept integers
+	// useful when the documents are not only written by the go driver
+	NumericPermissive
+)
+
+// Complex kinds are not mapped since they have no bson representation
+var numericMap = map[NumericMode]map[reflect.Kind][]string{
+	NumericStrict: {
+		reflect.Int:     {"int", "long"},
+		reflect.Int8:    {"int"},
+		reflect.Int16:   {"int"},
+		reflect.Int32:   {"int"},
+		reflect.Int64:   {"long"},
+		reflect.Uint:    {"long"},
+		reflect.Uint8:   {"int"},
+		reflect.Uint16:  {"int"},
+		reflect.Uint32:  {"long"},
+		reflect.Uint64:  {"long"},
+		reflect.Uintptr: {"long"},
+		reflect.Float32: {"double"},
+		reflect.Float64: {"double"},
+	},
+	NumericPermissive: {
+		reflect.Int:     {"int", "long"},
+		reflect.Int8:    {"int", "long"},
+		reflect.Int16:   {"int", "long"},
+		reflect.Int32:   {"int", "long"},
+		reflect.Int64:   {"int", "long"},
+		reflect.Uint:    {"int", "long"},
+		reflect.Uint8:   {"int", "long"},
+		reflect.Uint16:  {"int", "long"},
+		reflect.Uint32:  {"int", "long"},
+		reflect.Uint64:  {"int", "long"},
+		reflect.Uintptr: {"int", "long"},
+		reflect.Float32: {"double", "int", "long"},
+		reflect.Float64: {"double", "int", "long"},
+	},
+}
+
+// Well known types that would be described wrongly by their reflect.Kind
+// the bson type is the one the go driver writes for each of them
+var knownTypes = map[reflect.Type]string{
+	reflect.TypeOf(time.Time{}):       "date",
+	reflect.TypeOf(time.Duration(0)):  "long",
+	reflect.TypeOf([]byte{}):          "binData",
+	reflect.TypeOf(json.RawMessage{}): "binData",
+	reflect.TypeOf(net.IP{}):          "binData",
+	reflect.TypeOf(url.URL{}):         "string",
+	reflect.TypeOf(big.Int{}):         "decimal",
+	reflect.TypeOf(big.Float{}):       "decimal",
+	reflect.TypeOf(regexp.Regexp{}):   "regex",
+}
+
+// Get type from type tag, registered types, well known types or reflect.Kind
+// numeric kinds are mapped using the numeric policy
+func GetType(typeTag string, typ reflect.Type, numeric NumericMode) ([]string, error) {
+	typeArr, err := ParseTypes(typeTag)
 	if len(typeArr) > 0 || err != nil {
 		return typeArr, err
 	}
 
-	objType, ok := bsonMap[kind]
+	if types, ok := lookupRegistered(typ); ok {
+		return append([]string{}, types...), nil
+	}
+
+	if objType, ok := knownTypes[typ]; ok {
+		return []string{objType}, nil
+	}
+	// Named byte slices are written as binary data as well
+	if typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8 {
+		return []string{"binData"}, nil
+	}
+
+	if types, ok := numericMap[numeric][typ.Kind()]; ok {
+		return append([]string{}, types...), nil
+	}
+
+	// Interfaces can hold any value, so they are not restricted to a bson type
+	if typ.Kind() == reflect.Interface {
+		return []string{}, nil
+	}
+
+	objType, ok := bsonMap[typ.Kind()]
 	if !ok {
-		return typeArr, fmt.Errorf("type [%v] is not supported", kind)
+		return typeArr, fmt.Errorf("type [%v] is not supported", typ.Kind())
 	}
 
 	return []string{objType}, nil
 }
 
+// Get the valid bson types from a type tag
+func ParseTypes(typeTag string) ([]string, error) {
+	items, err := SplitList(typeTag)
+	if err != nil {
+		return []string{}, err
+	}
+
+	typeArr := make([]string, 0, len(items))
+	for _, item := range items {
+		typeArr = append(typeArr, item.Val)
+	}
+	return checkValidTypeArr(typeArr)
+}
+
 // Checks that all values in the typeArr are valid bson types
 func checkValidTypeArr(typeArr []string) ([]string, error) {
 	out := []string{}
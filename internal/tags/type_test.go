@@ -1,10 +1,18 @@
 package tags
 
 import (
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/url"
 	"reflect"
+	"regexp"
 	"testing"
+	"time"
 )
 
+type testBlob []byte
+
 type checkValidTypeArrTest struct {
 	arg, want []string
 	haveErr   bool
@@ -28,23 +36,53 @@ func TestCheckValidTypeArr(t *testing.T) {
 
 type getTypeTest struct {
 	arg1    string
-	arg2    reflect.Kind
+	arg2    reflect.Type
+	arg3    NumericMode
 	want    []string
 	haveErr bool
 }
 
 func TestGetType(t *testing.T) {
 	tests := []getTypeTest{
-		{"", reflect.String, []string{"string"}, false},
-		{",  ", reflect.String, []string{"string"}, false},
-		{"invalid", reflect.String, []string{}, true},
-		{",decimal", reflect.String, []string{"decimal"}, false},
-		{"", reflect.Chan, []string{}, true},
-		{" bool, double  ", reflect.String, []string{"bool", "double"}, false},
+		{"", reflect.TypeOf(""), NumericStrict, []string{"string"}, false},
+		{",  ", reflect.TypeOf(""), NumericStrict, []string{"string"}, false},
+		{"invalid", reflect.TypeOf(""), NumericStrict, []string{}, true},
+		{",decimal", reflect.TypeOf(""), NumericStrict, []string{"decimal"}, false},
+		{"", reflect.TypeOf(make(chan int)), NumericStrict, []string{}, true},
+		{" bool, double  ", reflect.TypeOf(""), NumericStrict, []string{"bool", "double"}, false},
+		{"", reflect.TypeOf(time.Time{}), NumericStrict, []string{"date"}, false},
+		{"string", reflect.TypeOf(time.Time{}), NumericStrict, []string{"string"}, false},
+		{"", reflect.TypeOf(time.Second), NumericStrict, []string{"long"}, false},
+		{"", reflect.TypeOf([]byte{}), NumericStrict, []string{"binData"}, false},
+		{"", reflect.TypeOf(json.RawMessage{}), NumericStrict, []string{"binData"}, false},
+		{"", reflect.TypeOf(net.IP{}), NumericStrict, []string{"binData"}, false},
+		{"", reflect.TypeOf(testBlob{}), NumericStrict, []string{"binData"}, false},
+		{"", reflect.TypeOf(url.URL{}), NumericStrict, []string{"string"}, false},
+		{"", reflect.TypeOf(big.Int{}), NumericStrict, []string{"decimal"}, false},
+		{"", reflect.TypeOf(big.Float{}), NumericStrict, []string{"decimal"}, false},
+		{"", reflect.TypeOf(regexp.Regexp{}), NumericStrict, []string{"regex"}, false},
+		{"", reflect.TypeOf(struct{}{}), NumericStrict, []string{"object"}, false},
+		{"", reflect.TypeOf(0), NumericStrict, []string{"int", "long"}, false},
+		{"", reflect.TypeOf(int16(0)), NumericStrict, []string{"int"}, false},
+		{"", reflect.TypeOf(int64(0)), NumericStrict, []string{"long"}, false},
+		{"", reflect.TypeOf(uint8(0)), NumericStrict, []string{"int"}, false},
+		{"", reflect.TypeOf(uint32(0)), NumericStrict, []string{"long"}, false},
+		{"", reflect.TypeOf(uintptr(0)), NumericStrict, []string{"long"}, false},
+		{"", reflect.TypeOf(float32(0)), NumericStrict, []string{"double"}, false},
+		{"", reflect.TypeOf(float64(0)), NumericStrict, []string{"double"}, false},
+		{"", reflect.TypeOf(complex64(0)), NumericStrict, []string{}, true},
+		{"", reflect.TypeOf([]interface{}{}).Elem(), NumericStrict, []string{}, false},
+		{"objectId", reflect.TypeOf([]interface{}{}).Elem(), NumericStrict, []string{"objectId"}, false},
+		{"", reflect.TypeOf(int16(0)), NumericPermissive, []string{"int", "long"}, false},
+		{"", reflect.TypeOf(uint64(0)), NumericPermissive, []string{"int", "long"}, false},
+		{"", reflect.TypeOf(float64(0)), NumericPermissive, []string{"double", "int", "long"}, false},
+		{"", reflect.TypeOf(complex128(0)), NumericPermissive, []string{}, true},
+		{"decimal", reflect.TypeOf(float64(0)), NumericPermissive, []string{"decimal"}, false},
+		{"", reflect.TypeOf(time.Second), NumericPermissive, []string{"long"}, false},
 	}
 
 	for _, test := range tests {
-		have, err := GetType(test.arg1, test.arg2)
+		have, err := GetType(test.arg1, test.arg2, test.arg3)
 
 		if !CompareArr(have, test.want) || (test.haveErr && err == nil) {
 			t.Errorf("\nGot: %#v;\nWant: %#v;\nErr: %#v", have, test.want, err)
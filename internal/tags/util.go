@@ -30,3 +30,13 @@ func CompareArr[T comparable](arr1 []T, arr2 []T) bool {
 	}
 	return true
 }
+
+// Checks if val is an item of arr
+func Contains[T comparable](arr []T, val T) bool {
+	for _, item := range arr {
+		if item == val {
+			return true
+		}
+	}
+	return false
+}
@@ -49,3 +49,24 @@ func TestCompareArr(t *testing.T) {
 		}
 	}
 }
+
+type containsTest struct {
+	arg1 []string
+	arg2 string
+	want bool
+}
+
+func TestContains(t *testing.T) {
+	tests := []containsTest{
+		{[]string{}, "a", false},
+		{nil, "", false},
+		{[]string{"a", "b", "c"}, "b", true},
+		{[]string{"a", "b", "c"}, "B", false},
+	}
+
+	for _, test := range tests {
+		if have := Contains(test.arg1, test.arg2); have != test.want {
+			t.Errorf("\nGot: %#v;\nWant: %#v;\nTest: %#v", have, test.want, test)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/s-augustovitko/mongo-schema-go/internal/tags"
+	"github.com/s-augustovitko/mongo-schema-go/pkg/schema/jsonschema"
+)
+
+const (
+	tagOneOf = "oneOf"
+	tagAnyOf = "anyOf"
+	tagAllOf = "allOf"
+	tagNot   = "not"
+)
+
+// Tags of the logical combinators, in the order they are added to the schema
+var combinatorTags = []string{tagAllOf, tagAnyOf, tagOneOf, tagNot}
+
+// Schema referenced by the combinator tags, either a schema or a go type that is described with its reflected schema
+type fragment struct {
+	Schema *jsonschema.Schema
+	Type   reflect.Type
+}
+
+// Fragments registered by name so they can be referenced in the combinator tags
+var fragments = struct {
+	sync.RWMutex
+	items map[string]fragment
+}{
+	items: map[string]fragment{},
+}
+
+// Registers a schema that can be referenced by name in the combinator tags, the schema is parsed so it is checked and copied
+func RegisterFragment(name string, schema BsonM) error {
+	if schema == nil {
+		return fmt.Errorf("[%v]: can not register a nil fragment", name)
+	}
+	parsed, err := jsonschema.Parse(schema)
+	if err != nil {
+		return fmt.Errorf("[%v]: invalid fragment, %v", name, err)
+	}
+	return registerFragment(name, fragment{Schema: parsed})
+}
+
+// Registers a type that can be referenced by name in the combinator tags, pointers are resolved to the type they point to
+func RegisterFragmentType(name string, typ reflect.Type) error {
+	if typ == nil {
+		return fmt.Errorf("[%v]: can not register a nil type", name)
+	}
+	return registerFragment(name, fragment{Type: indirectType(typ)})
+}
+
+func registerFragment(name string, item fragment) error {
+	if !isFragmentName(name) {
+		return fmt.Errorf("invalid fragment name [%v], it can not be empty or have spaces, commas, quotes or brackets", name)
+	}
+
+	fragments.Lock()
+	defer fragments.Unlock()
+	fragments.items[name] = item
+	return nil
+}
+
+// Checks that the name can be written in the combinator tags without quotes
+func isFragmentName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, " ,'\\(){}[]")
+}
+
+func lookupFragment(name string) (fragment, bool) {
+	fragments.RLock()
+	defer fragments.RUnlock()
+
+	item, ok := fragments.items[name]
+	return item, ok
+}
+
+// Combinator keyword with the fragments of its tag
+type combinator struct {
+	Keyword   string
+	Fragments []fragment
+}
+
+// Parses the combinator tags of the field, each value is the name of a registered fragment or a json object
+// the not tag must have a single value
+func parseCombinators(field reflect.StructField) ([]combinator, error) {
+	var out []combinator
+	for _, tag := range combinatorTags {
+		tagValue := field.Tag.Get(tag)
+		if tagValue == "" {
+			continue
+		}
+
+		items, err := tags.SplitNested(tagValue)
+		if err != nil {
+			return out, tags.WithTagName(tag, err)
+		}
+		if tag == tagNot && len(items) != 1 {
+			return out, &tags.SyntaxError{Tag: tag, Msg: fmt.Sprintf("expected one fragment, got %v", len(items))}
+		}
+
+		item := combinator{Keyword: tag}
+		for _, value := range items {
+			frag, err := parseFragment(value)
+			if err != nil {
+				return out, &tags.SyntaxError{Tag: tag, Offset: value.Offset, Msg: err.Error()}
+			}
+			item.Fragments = append(item.Fragments, frag)
+		}
+		if len(item.Fragments) > 0 {
+			out = append(out, item)
+		}
+	}
+	return out, nil
+}
+
+// Parses a value of a combinator tag, unquoted values that start with { are json objects
+func parseFragment(value tags.TagItem) (fragment, error) {
+	if !value.Quoted && strings.HasPrefix(value.Val, "{") {
+		schema, err := jsonschema.ParseJSON([]byte(value.Val))
+		return fragment{Schema: schema}, err
+	}
+
+	item, ok := lookupFragment(value.Val)
+	if !ok {
+		return item, fmt.Errorf("fragment [%v] is not registered", value.Val)
+	}
+	return item, nil
+}
+
+// Adds the combinators to obj, types are described with their reflected schema at the position of the walk
+func addCombinators(combinators []combinator, opts Options, w walk, obj *jsonschema.Schema) []error {
+	errors := []error{}
+	for _, item := range combinators {
+		schemas := make([]*jsonschema.Schema, 0, len(item.Fragments))
+		for _, frag := range item.Fragments {
+			schema, errs := fragmentSchema(frag, opts, w)
+			errors = append(errors, errs...)
+			schemas = append(schemas, schema)
+		}
+
+		switch item.Keyword {
+		case tagAllOf:
+			obj.AllOf = schemas
+		case tagAnyOf:
+			obj.AnyOf = schemas
+		case tagOneOf:
+			obj.OneOf = schemas
+		case tagNot:
+			obj.Not = schemas[0]
+		}
+	}
+	return errors
+}
+
+// Gets the schema of the fragment, schemas are copied so they are not shared between fields
+func fragmentSchema(frag fragment, opts Options, w walk) (*jsonschema.Schema, []error) {
+	if frag.Type != nil {
+		return typeSchema(frag.Type, opts, w)
+	}
+	return frag.Schema.Copy(), []error{}
+}
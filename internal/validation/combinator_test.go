@@ -0,0 +1,135 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/s-augustovitko/mongo-schema-go/internal/tags"
+	"github.com/s-augustovitko/mongo-schema-go/pkg/schema/jsonschema"
+)
+
+type combinatorTestCard struct {
+	Number string `validation:"required"`
+}
+
+type combinatorTestBank struct {
+	Iban string `validation:"required"`
+}
+
+type registerFragmentTest struct {
+	arg1    string
+	arg2    BsonM
+	haveErr bool
+}
+
+func TestRegisterFragment(t *testing.T) {
+	tests := []registerFragmentTest{
+		{"testNonEmpty", BsonM{"minLength": 1}, false},
+		{"", BsonM{"minLength": 1}, true},
+		{"test name", BsonM{"minLength": 1}, true},
+		{"test,name", BsonM{"minLength": 1}, true},
+		{"test{name}", BsonM{"minLength": 1}, true},
+		{"testNil", nil, true},
+		{"testInvalid", BsonM{"minLength": "1"}, true},
+	}
+
+	for _, test := range tests {
+		err := RegisterFragment(test.arg1, test.arg2)
+
+		if haveErr := err != nil; haveErr != test.haveErr {
+			t.Errorf("\nArg: %v, %#v;\nErr: %#v", test.arg1, test.arg2, err)
+		}
+	}
+
+	if err := RegisterFragmentType("testNilType", nil); err == nil {
+		t.Errorf("\nGot: %#v;\nWant: an error", err)
+	}
+}
+
+type createJSONSchemaTestCombinators struct {
+	_        struct{}    `anyOf:"{\"required\": [\"email\"]}, {\"required\": [\"phone\"]}"`
+	Email    string      `bson:"email,omitempty"`
+	Phone    string      `bson:"phone,omitempty"`
+	Name     string      `bson:"name" not:"{\"enum\": [\"\"]}"`
+	Payment  interface{} `bson:"payment" oneOf:"testCard,testBank"`
+	Id       interface{} `bson:"id" type:"objectId,string" anyOf:"testNonEmpty"`
+	Code     string      `bson:"code" allOf:"testNonEmpty,{\"maxLength\": 8}"`
+	Invalid  string      `bson:"invalid" oneOf:"testMissing"`
+	Invalid2 string      `bson:"invalid2" not:"testNonEmpty,testNonEmpty"`
+	Invalid3 string      `bson:"invalid3" allOf:"{\"minLength\": }"`
+}
+
+func TestCreateJSONSchemaCombinators(t *testing.T) {
+	if err := RegisterFragment("testNonEmpty", BsonM{"minLength": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterFragmentType("testCard", reflect.TypeOf(&combinatorTestCard{})); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterFragmentType("testBank", reflect.TypeOf(combinatorTestBank{})); err != nil {
+		t.Fatal(err)
+	}
+
+	want := BsonM{
+		"anyOf": []BsonM{{"required": []string{"email"}}, {"required": []string{"phone"}}},
+		"properties": BsonM{
+			"email": BsonM{"bsonType": []string{"string"}},
+			"phone": BsonM{"bsonType": []string{"string"}},
+			"name":  BsonM{"bsonType": []string{"string"}, "not": BsonM{"enum": []interface{}{""}}},
+			"payment": BsonM{"oneOf": []BsonM{
+				{"bsonType": []string{"object"}, "properties": BsonM{"number": BsonM{"bsonType": []string{"string"}}}, "required": []string{"number"}},
+				{"bsonType": []string{"object"}, "properties": BsonM{"iban": BsonM{"bsonType": []string{"string"}}}, "required": []string{"iban"}}}},
+			"id":   BsonM{"bsonType": []string{"objectId", "string"}, "anyOf": []BsonM{{"minLength": 1}}},
+			"code": BsonM{"bsonType": []string{"string"}, "allOf": []BsonM{{"minLength": 1}, {"maxLength": 8}}},
+		},
+		"required": []string{},
+	}
+	wantErrs := []string{
+		"[invalid]: invalid [oneOf] tag at offset 0: fragment [testMissing] is not registered",
+		"[invalid2]: invalid [not] tag at offset 0: expected one fragment, got 2",
+		"[invalid3]: invalid [allOf] tag at offset 0: invalid json object, invalid character '}' looking for beginning of value",
+	}
+
+	haveSchema := &jsonschema.Schema{}
+	errs := CreateJSONSchema(reflect.TypeOf(createJSONSchemaTestCombinators{}), Options{}, haveSchema)
+	have := haveSchema.ToBsonM()
+
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("Field: Object;\nGot: %#v;\nWant: %#v;", have, want)
+	}
+	haveErrs := []string{}
+	for _, err := range errs {
+		haveErrs = append(haveErrs, err.Error())
+	}
+	if !tags.CompareArr(haveErrs, wantErrs) {
+		t.Errorf("Field: Errors;\nGot: %#v;\nWant: %#v", haveErrs, wantErrs)
+	}
+
+	// Fragments are copied, so changing the schema of a field does not change the others
+	have["properties"].(BsonM)["id"].(BsonM)["anyOf"].([]BsonM)[0]["minLength"] = 2
+	againSchema := &jsonschema.Schema{}
+	CreateJSONSchema(reflect.TypeOf(createJSONSchemaTestCombinators{}), Options{}, againSchema)
+	again := againSchema.ToBsonM()
+	if !reflect.DeepEqual(want, again) {
+		t.Errorf("Field: Copy;\nGot: %#v;\nWant: %#v;", again, want)
+	}
+}
+
+type createJSONSchemaTestCombinatorErrs struct {
+	_    struct{} `oneOf:"testMissing"`
+	Name string
+}
+
+func TestObjectCombinatorErrs(t *testing.T) {
+	haveSchema := &jsonschema.Schema{}
+	errs := CreateJSONSchema(reflect.TypeOf(createJSONSchemaTestCombinatorErrs{}), Options{}, haveSchema)
+	have := haveSchema.ToBsonM()
+
+	want := "[_]: invalid [oneOf] tag at offset 0: fragment [testMissing] is not registered"
+	if len(errs) != 1 || errs[0].Error() != want || errs[0].(ErrorWithTag).Path() != "_" {
+		t.Errorf("\nGot: %#v;\nWant: %#v", errs, want)
+	}
+	if _, ok := have["oneOf"]; ok {
+		t.Errorf("\nGot: %#v;\nWant: no oneOf", have)
+	}
+}
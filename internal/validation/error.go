@@ -2,6 +2,7 @@ package validation
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/s-augustovitko/mongo-schema-go/internal/tags"
 )
@@ -9,12 +10,14 @@ import (
 type ErrorWithTag interface {
 	Name() string
 	Tag() string
+	Path() string
 	Error() string
 }
 
 type errorWithTag struct {
 	name  string
 	tag   string
+	path  string
 	error string
 }
 
@@ -33,8 +36,20 @@ func (e errorWithTag) Tag() string {
 	return e.tag
 }
 
-// Gets the error message
+// Get Path value, the dotted path of the field from the root struct (eg. "address.zip")
+// If path is empty tag value will be retrieved
+func (e errorWithTag) Path() string {
+	if e.path == "" {
+		return e.Tag()
+	}
+	return e.path
+}
+
+// Gets the error message, with the path of the field in the schema or its name if it has no path
 func (e errorWithTag) Error() string {
+	if e.path != "" {
+		return fmt.Sprintf("[%v]: %v", e.path, e.error)
+	}
 	return fmt.Sprintf("[%v]: %v", e.Name(), e.error)
 }
 
@@ -47,3 +62,31 @@ func createErrorWithTag(tag, name string, err error) ErrorWithTag {
 		error: err.Error(),
 	}
 }
+
+// Creates error with the dotted path of a field (eg. "address.zip"), the tag and name are the last part of the path
+func CreateErrorWithPath(path string, err error) ErrorWithTag {
+	name := path[strings.LastIndex(path, ".")+1:]
+	return errorWithTag{
+		tag:   name,
+		name:  name,
+		path:  path,
+		error: err.Error(),
+	}
+}
+
+// Sets the path of the errors that do not have one yet
+// path is the path of the object that contains the fields of the errors
+func addErrorsPath(path string, errs []error) []error {
+	out := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if item, ok := err.(errorWithTag); ok && item.path == "" {
+			item.path = item.Tag()
+			if path != "" {
+				item.path = path + "." + item.Tag()
+			}
+			err = item
+		}
+		out = append(out, err)
+	}
+	return out
+}
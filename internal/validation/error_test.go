@@ -2,6 +2,7 @@ package validation
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -21,6 +22,12 @@ func TestCreateError(t *testing.T) {
 		t.Errorf("\nGot: %#v;\nWant: %#v", have.Name(), wantName)
 	}
 
+	// Errors of the walk have the path of the field in the schema
+	withPath := errorWithTag{tag: "zip_code", name: "ZipCode", path: "address.zip_code", error: "Some Error"}
+	if want := "[address.zip_code]: Some Error"; withPath.Error() != want {
+		t.Errorf("\nGot: %#v;\nWant: %#v", withPath.Error(), want)
+	}
+
 	have = createErrorWithTag("", wantName, fmt.Errorf("Some Error"))
 	if have.Error() != want {
 		t.Errorf("\nGot: %#v;\nWant: %#v", have.Error(), want)
@@ -32,3 +39,31 @@ func TestCreateError(t *testing.T) {
 		t.Errorf("\nGot: %#v;\nWant: %#v", have.Name(), wantName)
 	}
 }
+
+type addErrorsPathTest struct {
+	arg1 string
+	arg2 []error
+	want []string
+}
+
+func TestAddErrorsPath(t *testing.T) {
+	tests := []addErrorsPathTest{
+		{"", []error{createErrorWithTag("tag", "Tag", fmt.Errorf("err"))}, []string{"tag"}},
+		{"address", []error{createErrorWithTag("", "Zip", fmt.Errorf("err"))}, []string{"address.zip"}},
+		{"a.b", []error{
+			createErrorWithTag("c", "C", fmt.Errorf("err")),
+			errorWithTag{tag: "d", name: "D", path: "x.d", error: "err"},
+		}, []string{"a.b.c", "x.d"}},
+	}
+
+	for _, test := range tests {
+		have := []string{}
+		for _, err := range addErrorsPath(test.arg1, test.arg2) {
+			have = append(have, err.(ErrorWithTag).Path())
+		}
+
+		if !reflect.DeepEqual(have, test.want) {
+			t.Errorf("\nGot: %#v;\nWant: %#v", have, test.want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/s-augustovitko/mongo-schema-go/internal/tags"
+)
+
+// A field of a struct, the fields of embedded and inline structs are promoted to the struct
+type structField struct {
+	Field  reflect.StructField
+	Name   string
+	Index  []int
+	Tagged bool
+	// Inline maps have no name, their values are the additional properties of the struct
+	IsInlineMap bool
+}
+
+// Gets the fields of a struct following the go promotion rules (same as encoding/json), the fields of embedded and inline structs are promoted
+// unexported fields are skipped and embedded structs are flattened unless their tags have a name (eg. bson:"audit")
+// if more than one field has the same name the shallower one wins, then the tagged one,
+// otherwise all of them are dropped and a warning is returned
+func structFields(typ reflect.Type, opts Options) ([]structField, []error) {
+	fields := []structField{}
+	next := []structField{{Field: reflect.StructField{Type: typ}}}
+	visited := map[reflect.Type]bool{}
+
+	for len(next) > 0 {
+		current := next
+		next = nil
+
+		// Types embedded more than once at the same depth are walked each time, so their fields are ambiguous
+		levelVisited := map[reflect.Type]bool{}
+		for _, parent := range current {
+			parentTyp := indirectType(parent.Field.Type)
+			if visited[parentTyp] {
+				continue
+			}
+			levelVisited[parentTyp] = true
+
+			for i := 0; i < parentTyp.NumField(); i++ {
+				field := parentTyp.Field(i)
+				fieldTyp := indirectType(field.Type)
+				fieldTag, bsonTag := field.Tag.Get(tagField), field.Tag.Get(tagBson)
+				if tags.IsSkipped(fieldTag, bsonTag) {
+					continue
+				}
+
+				name, isInline := tags.GetTagWithPolicy(fieldTag, bsonTag, field.Name, opts.NamingPolicy)
+				item := structField{
+					Field:  field,
+					Name:   name,
+					Index:  append(append([]int{}, parent.Index...), i),
+					Tagged: tags.HasName(fieldTag, bsonTag),
+				}
+
+				// Unexported embedded structs can still have exported fields when they are promoted
+				promoted := isPromoted(field, fieldTyp, isInline, item.Tagged, opts)
+				if !field.IsExported() && !(field.Anonymous && promoted) {
+					continue
+				}
+				if promoted {
+					next = append(next, item)
+					continue
+				}
+				item.IsInlineMap = isInline && fieldTyp.Kind() == reflect.Map
+				fields = append(fields, item)
+			}
+		}
+
+		for visitedTyp := range levelVisited {
+			visited[visitedTyp] = true
+		}
+	}
+
+	return dominantFields(fields)
+}
+
+// Checks if the fields of an embedded or inline struct are promoted to the parent struct, embedded structs with a name in their tags are objects
+// structs that are not objects (eg. time.Time) or provide their own schema are never promoted
+func isPromoted(field reflect.StructField, typ reflect.Type, isInline, isTagged bool, opts Options) bool {
+	if typ.Kind() != reflect.Struct || (!isInline && (!field.Anonymous || isTagged)) {
+		return false
+	}
+	if _, ok := getProvider(typ); ok {
+		return false
+	}
+
+	types, err := tags.GetType(field.Tag.Get(tagType), typ, opts.NumericPolicy)
+	return err == nil && tags.Contains(types, "object")
+}
+
+// Keeps the dominant field of each name and sorts the fields in the order they are declared
+func dominantFields(fields []structField) ([]structField, []error) {
+	errors := []error{}
+	byName := map[string][]structField{}
+	out := []structField{}
+
+	for _, field := range fields {
+		if field.IsInlineMap {
+			out = append(out, field)
+			continue
+		}
+		byName[field.Name] = append(byName[field.Name], field)
+	}
+
+	for name, named := range byName {
+		if field, ok := dominantField(named); ok {
+			out = append(out, field)
+			continue
+		}
+		errors = append(errors, createErrorWithTag(name, named[0].Field.Name, fmt.Errorf("field is ambiguous, it is defined more than once at the same depth")))
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return compareIndex(out[i].Index, out[j].Index)
+	})
+	sort.Slice(errors, func(i, j int) bool {
+		return errors[i].(ErrorWithTag).Tag() < errors[j].(ErrorWithTag).Tag()
+	})
+	return out, errors
+}
+
+// Gets the shallowest field, if there is more than one the tagged field is used
+func dominantField(fields []structField) (structField, bool) {
+	sort.SliceStable(fields, func(i, j int) bool {
+		if len(fields[i].Index) != len(fields[j].Index) {
+			return len(fields[i].Index) < len(fields[j].Index)
+		}
+		return fields[i].Tagged && !fields[j].Tagged
+	})
+
+	if len(fields) > 1 && len(fields[0].Index) == len(fields[1].Index) && fields[0].Tagged == fields[1].Tagged {
+		return structField{}, false
+	}
+	return fields[0], true
+}
+
+// Checks if index a is declared before index b
+func compareIndex(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
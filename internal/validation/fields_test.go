@@ -0,0 +1,129 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testFieldsBase struct {
+	ID      interface{} `bson:"_id"`
+	Name    string
+	Email   string
+	private string
+}
+
+type testFieldsOther struct {
+	Email string `bson:"email"`
+	Phone string `bson:"phone"`
+	Fax   string
+}
+
+type testFieldsTagged struct {
+	Phone string
+	Fax   string `bson:"fax"`
+}
+
+type testFieldsSelf struct {
+	*testFieldsSelf `bson:",inline"`
+	Value           string
+}
+
+type TestFieldsEmbedded struct {
+	Code string
+}
+
+type testFieldsPrivate struct {
+	Secret string
+}
+
+type TestFieldsNamed struct {
+	Code string
+}
+
+type testFieldsTest struct {
+	testFieldsBase   `bson:",inline"`
+	*testFieldsOther `bson:",inline"`
+	testFieldsTagged `field:",inline"`
+	testFieldsSelf   `bson:",inline"`
+	time.Time
+	TestFieldsEmbedded
+	testFieldsPrivate
+	TestFieldsNamed `bson:"named"`
+	Nested          testFieldsBase `bson:"nested"`
+	Name            string         `bson:"name"`
+	private         string
+	Skip            string            `bson:"-"`
+	Extra           map[string]string `bson:",inline"`
+}
+
+func TestStructFields(t *testing.T) {
+	want := []string{"_id", "email", "phone", "fax", "value", "time", "code", "secret", "named", "nested", "name", "extra"}
+	wantErrs := []string{}
+
+	fields, errs := structFields(reflect.TypeOf(testFieldsTest{}), Options{})
+
+	have := []string{}
+	for _, field := range fields {
+		have = append(have, field.Name)
+	}
+	haveErrs := []string{}
+	for _, err := range errs {
+		haveErrs = append(haveErrs, err.(ErrorWithTag).Tag())
+	}
+
+	if !reflect.DeepEqual(have, want) || !reflect.DeepEqual(haveErrs, wantErrs) {
+		t.Errorf("\nGot: %#v, %#v;\nWant: %#v, %#v;", have, haveErrs, want, wantErrs)
+	}
+	if email := fields[1]; !reflect.DeepEqual(email.Index, []int{1, 0}) {
+		t.Errorf("\nTagged email should be promoted;\nGot: %#v;", email)
+	}
+}
+
+type testFieldsAmbiguous struct {
+	testFieldsBase   `bson:",inline"`
+	testFieldsTagged `bson:",inline"`
+	Other            testFieldsTagged `bson:",inline"`
+	Other2           testFieldsTagged `bson:",inline"`
+}
+
+func TestStructFieldsAmbiguous(t *testing.T) {
+	want := []string{"_id", "name", "email"}
+	wantErrs := []string{"fax", "phone"}
+
+	fields, errs := structFields(reflect.TypeOf(testFieldsAmbiguous{}), Options{})
+
+	have := []string{}
+	for _, field := range fields {
+		have = append(have, field.Name)
+	}
+	haveErrs := []string{}
+	for _, err := range errs {
+		haveErrs = append(haveErrs, err.(ErrorWithTag).Tag())
+	}
+
+	if !reflect.DeepEqual(have, want) || !reflect.DeepEqual(haveErrs, wantErrs) {
+		t.Errorf("\nGot: %#v, %#v;\nWant: %#v, %#v;", have, haveErrs, want, wantErrs)
+	}
+}
+
+type compareIndexTest struct {
+	arg1, arg2 []int
+	want       bool
+}
+
+func TestCompareIndex(t *testing.T) {
+	tests := []compareIndexTest{
+		{[]int{0}, []int{1}, true},
+		{[]int{1}, []int{0, 3}, false},
+		{[]int{0, 1}, []int{0, 2}, true},
+		{[]int{0}, []int{0, 1}, true},
+		{[]int{0, 1}, []int{0, 1}, false},
+	}
+
+	for _, test := range tests {
+		if have := compareIndex(test.arg1, test.arg2); have != test.want {
+			t.Errorf("\nGot: %#v;\nWant: %#v;\nTest: %#v", have, test.want, test)
+		}
+	}
+}
@@ -2,9 +2,12 @@ package validation
 
 import (
 	"fmt"
+	"math"
+	"reflect"
 	"strconv"
 
 	"github.com/s-augustovitko/mongo-schema-go/internal/tags"
+	"github.com/s-augustovitko/mongo-schema-go/pkg/schema/jsonschema"
 )
 
 type BsonM = map[string]interface{}
@@ -14,109 +17,198 @@ type WithVal[T any] struct {
 	Val    T
 }
 
-// If item exists then sets the obj[field] to the value
-// if (item.Exists) obj[field] = item.Val
-func (item WithVal[T]) SetVal(field string, obj *BsonM) {
+// If item exists then sets the optional value of the schema to it
+// if (item.Exists) *out = item.Val
+func (item WithVal[T]) setOptional(out *jsonschema.Optional[T]) {
 	if item.Exists {
-		(*obj)[field] = item.Val
+		*out = jsonschema.CreateVal(item.Val)
 	}
 }
 
+// Gets item if it exists, otherwise other
+func (item WithVal[T]) Or(other WithVal[T]) WithVal[T] {
+	if item.Exists {
+		return item
+	}
+	return other
+}
+
 func CreateVal[T any](val T) WithVal[T] {
 	return WithVal[T]{Val: val, Exists: true}
 }
 
+// Min and Max adapt to the bson type of the field (minimum, minLength, minItems or minProperties)
+// the other bounds only apply to their keyword and take precedence over Min and Max
 type Validation struct {
-	UniqueItems  bool
-	Required     bool
-	Min          WithVal[float64]
-	Max          WithVal[float64]
-	Pattern      WithVal[string]
-	PatternProps WithVal[string]
-	MultipleOf   WithVal[float64]
+	UniqueItems   bool
+	Required      bool
+	Min           WithVal[float64]
+	Max           WithVal[float64]
+	ExclusiveMin  WithVal[float64]
+	ExclusiveMax  WithVal[float64]
+	MinLength     WithVal[int]
+	MaxLength     WithVal[int]
+	MinItems      WithVal[int]
+	MaxItems      WithVal[int]
+	MinProperties WithVal[int]
+	MaxProperties WithVal[int]
+	Pattern       WithVal[string]
+	PatternProps  WithVal[string]
+	MultipleOf    WithVal[float64]
 }
 
+// Parses the validation (or items) tag, values can be quoted (eg. "pattern='^[a-z]{1,5}$'"), see tags.SplitKeyValues
 func parseValidation(validation string) (Validation, error) {
 	var out Validation
 
-	validItems := tags.SplitTrim(validation, ",")
-	if len(validItems) == 0 {
-		return out, nil
+	validItems, err := tags.SplitKeyValues(validation)
+	if err != nil {
+		return out, err
 	}
 
+	offsets := map[string]int{}
 	for _, item := range validItems {
-		val := tags.SplitTrim(item, "=")
-		if len(val) == 0 || val[0] == "" {
-			continue
-		}
-
-		if val[0] == "required" || val[0] == "uniqueItems" {
-			if len(val) != 1 {
-				return out, fmt.Errorf("%v validation does not need a value", val)
+		offsets[item.Key] = item.Offset
+		if item.Key == "required" || item.Key == "uniqueItems" {
+			if item.HasVal {
+				return out, &tags.SyntaxError{Offset: item.Offset, Msg: fmt.Sprintf("[%v] validation does not need a value", item.Key)}
 			}
-		} else if len(val) != 2 {
-			return out, fmt.Errorf("%v validation requires a value", val)
+		} else if !item.HasVal || (item.Val == "" && !item.Quoted) {
+			return out, &tags.SyntaxError{Offset: item.Offset, Msg: fmt.Sprintf("[%v] validation requires a value", item.Key)}
 		}
 
-		switch val[0] {
+		switch item.Key {
 		case "required":
 			out.Required = true
 		case "uniqueItems":
 			out.UniqueItems = true
 		case "min":
-			floatVal, err := strconv.ParseFloat(val[1], 64)
-			if err != nil {
-				return out, err
-			}
-			out.Min = CreateVal(floatVal)
+			out.Min, err = parseFloatVal(item.Val)
 		case "max":
-			floatVal, err := strconv.ParseFloat(val[1], 64)
-			if err != nil {
-				return out, err
-			}
-			out.Max = CreateVal(floatVal)
+			out.Max, err = parseFloatVal(item.Val)
+		case "exclusiveMinimum":
+			out.ExclusiveMin, err = parseFloatVal(item.Val)
+		case "exclusiveMaximum":
+			out.ExclusiveMax, err = parseFloatVal(item.Val)
+		case "minLength":
+			out.MinLength, err = parseIntVal(item.Val)
+		case "maxLength":
+			out.MaxLength, err = parseIntVal(item.Val)
+		case "minItems":
+			out.MinItems, err = parseIntVal(item.Val)
+		case "maxItems":
+			out.MaxItems, err = parseIntVal(item.Val)
+		case "minProperties":
+			out.MinProperties, err = parseIntVal(item.Val)
+		case "maxProperties":
+			out.MaxProperties, err = parseIntVal(item.Val)
 		case "multipleOf":
-			floatVal, err := strconv.ParseFloat(val[1], 64)
-			if err != nil {
-				return out, err
-			}
-			out.MultipleOf = CreateVal(floatVal)
+			out.MultipleOf, err = parseFloatVal(item.Val)
 		case "pattern":
-			out.Pattern = CreateVal(val[1])
+			out.Pattern = CreateVal(item.Val)
 		case "patternProperties":
-			out.PatternProps = CreateVal(val[1])
+			out.PatternProps = CreateVal(item.Val)
 		default:
-			return out, fmt.Errorf("invalid validation value: %v", val)
+			return out, &tags.SyntaxError{Offset: item.Offset, Msg: fmt.Sprintf("invalid validation [%v]", item.Key)}
 		}
+		if err != nil {
+			return out, &tags.SyntaxError{Offset: item.Offset, Msg: fmt.Sprintf("invalid value of [%v], %v", item.Key, err)}
+		}
+	}
+
+	return out, checkValidation(out, offsets)
+}
+
+// Checks that the bounds of the validation do not contradict each other
+// offsets are the positions of the keys in the tag, errors are at the offset of the key that comes last
+func checkValidation(validation Validation, offsets map[string]int) error {
+	if validation.Min.Exists && validation.ExclusiveMin.Exists {
+		return boundsError(offsets, "min", "exclusiveMinimum", "can not be used together")
+	}
+	if validation.Max.Exists && validation.ExclusiveMax.Exists {
+		return boundsError(offsets, "max", "exclusiveMaximum", "can not be used together")
 	}
 
-	if out.Min.Exists && out.Max.Exists && out.Max.Val < out.Min.Val {
-		return out, fmt.Errorf("invalid [min,max] values, min can not be larger than max")
+	lowerName := boundName(validation.ExclusiveMin, "exclusiveMinimum", "min")
+	upperName := boundName(validation.ExclusiveMax, "exclusiveMaximum", "max")
+	exclusive := validation.ExclusiveMin.Exists || validation.ExclusiveMax.Exists
+	if err := checkBounds(lowerName, upperName, validation.ExclusiveMin.Or(validation.Min), validation.ExclusiveMax.Or(validation.Max), exclusive, offsets); err != nil {
+		return err
+	}
+	if err := checkBounds("minLength", "maxLength", validation.MinLength, validation.MaxLength, false, offsets); err != nil {
+		return err
+	}
+	if err := checkBounds("minItems", "maxItems", validation.MinItems, validation.MaxItems, false, offsets); err != nil {
+		return err
+	}
+	return checkBounds("minProperties", "maxProperties", validation.MinProperties, validation.MaxProperties, false, offsets)
+}
+
+// Checks that min is not larger than max when both exist, if exclusive they can not be equal either
+func checkBounds[T int | float64](minName, maxName string, min, max WithVal[T], exclusive bool, offsets map[string]int) error {
+	if !min.Exists || !max.Exists || min.Val < max.Val || (min.Val == max.Val && !exclusive) {
+		return nil
+	}
+	if exclusive {
+		return boundsError(offsets, minName, maxName, fmt.Sprintf("%v must be lower than %v", minName, maxName))
+	}
+	return boundsError(offsets, minName, maxName, fmt.Sprintf("%v can not be larger than %v", minName, maxName))
+}
+
+// Creates the error of two contradictory keys at the offset of the one that comes last
+func boundsError(offsets map[string]int, first, second, msg string) error {
+	offset := offsets[first]
+	if offsets[second] > offset {
+		offset = offsets[second]
+	}
+	return &tags.SyntaxError{Offset: offset, Msg: fmt.Sprintf("invalid [%v,%v] values, %v", first, second, msg)}
+}
+
+// Parses a float validation value
+func parseFloatVal(val string) (WithVal[float64], error) {
+	floatVal, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return WithVal[float64]{}, err
+	}
+	return CreateVal(floatVal), nil
+}
+
+// Parses a length validation value, lengths can not be negative
+func parseIntVal(val string) (WithVal[int], error) {
+	intVal, err := strconv.Atoi(val)
+	if err != nil {
+		return WithVal[int]{}, err
 	}
-	return out, nil
+	if intVal < 0 {
+		return WithVal[int]{}, fmt.Errorf("invalid value [%v], it can not be negative", intVal)
+	}
+	return CreateVal(intVal), nil
 }
 
-func addValidations(types []string, validation Validation, obj *BsonM) {
+func addValidations(types []string, validation Validation, obj *jsonschema.Schema) {
 	for _, kind := range types {
 		switch kind {
 		case "double", "int", "long", "decimal":
-			validation.Max.SetVal("maximum", obj)
-			validation.Min.SetVal("minimum", obj)
-			validation.MultipleOf.SetVal("multipleOf", obj)
-		case "string":
-			floatToIntVal(validation.Max).SetVal("maxLength", obj)
-			floatToIntVal(validation.Min).SetVal("minLength", obj)
-			validation.Pattern.SetVal("pattern", obj)
-			if validation.Pattern.Exists {
-				validation.PatternProps.SetVal("patternProperties", obj)
+			validation.ExclusiveMax.Or(validation.Max).setOptional(&obj.Maximum)
+			validation.ExclusiveMin.Or(validation.Min).setOptional(&obj.Minimum)
+			if validation.ExclusiveMax.Exists {
+				obj.ExclusiveMaximum = true
 			}
+			if validation.ExclusiveMin.Exists {
+				obj.ExclusiveMinimum = true
+			}
+			validation.MultipleOf.setOptional(&obj.MultipleOf)
+		case "string":
+			validation.MaxLength.Or(floatToIntVal(validation.Max)).setOptional(&obj.MaxLength)
+			validation.MinLength.Or(floatToIntVal(validation.Min)).setOptional(&obj.MinLength)
+			validation.Pattern.setOptional(&obj.Pattern)
 		case "array":
-			floatToIntVal(validation.Max).SetVal("maxItems", obj)
-			floatToIntVal(validation.Min).SetVal("minItems", obj)
-			(*obj)["uniqueItems"] = validation.UniqueItems
+			validation.MaxItems.Or(floatToIntVal(validation.Max)).setOptional(&obj.MaxItems)
+			validation.MinItems.Or(floatToIntVal(validation.Min)).setOptional(&obj.MinItems)
+			obj.UniqueItems = jsonschema.CreateVal(validation.UniqueItems)
 		case "object":
-			floatToIntVal(validation.Max).SetVal("maxProperties", obj)
-			floatToIntVal(validation.Min).SetVal("minProperties", obj)
+			validation.MaxProperties.Or(floatToIntVal(validation.Max)).setOptional(&obj.MaxProperties)
+			validation.MinProperties.Or(floatToIntVal(validation.Min)).setOptional(&obj.MinProperties)
 		}
 	}
 }
@@ -125,3 +217,87 @@ func addValidations(types []string, validation Validation, obj *BsonM) {
 func floatToIntVal(item WithVal[float64]) WithVal[int] {
 	return WithVal[int]{Val: int(item.Val), Exists: item.Exists}
 }
+
+type bounds struct {
+	Min WithVal[float64]
+	Max WithVal[float64]
+}
+
+// Values that can be stored by the integer kinds smaller than 64 bits and the unsigned kinds
+// the driver can not write unsigned values larger than math.MaxInt64 so there is no need for a maximum on them
+var kindBounds = map[reflect.Kind]bounds{
+	reflect.Int8:    {CreateVal[float64](math.MinInt8), CreateVal[float64](math.MaxInt8)},
+	reflect.Int16:   {CreateVal[float64](math.MinInt16), CreateVal[float64](math.MaxInt16)},
+	reflect.Int32:   {CreateVal[float64](math.MinInt32), CreateVal[float64](math.MaxInt32)},
+	reflect.Uint8:   {CreateVal[float64](0), CreateVal[float64](math.MaxUint8)},
+	reflect.Uint16:  {CreateVal[float64](0), CreateVal[float64](math.MaxUint16)},
+	reflect.Uint32:  {CreateVal[float64](0), CreateVal[float64](math.MaxUint32)},
+	reflect.Uint:    {Min: CreateVal[float64](0)},
+	reflect.Uint64:  {Min: CreateVal[float64](0)},
+	reflect.Uintptr: {Min: CreateVal[float64](0)},
+}
+
+// Adds the bounds of the kind as min and max validations, bounds outside of the values of the kind are clamped to them
+// returns an error if no value of the kind is inside the bounds (eg. uint8 with min=300)
+// only applies if all the types are numeric, so min and max are not used as lengths, "null" is skipped since it has no bounds
+func addKindBounds(kind reflect.Kind, types []string, validation *Validation) error {
+	kindBound, ok := kindBounds[kind]
+	if !ok {
+		return nil
+	}
+	numeric := false
+	for _, typ := range types {
+		if typ == "null" {
+			continue
+		}
+		if typ != "int" && typ != "long" && typ != "double" && typ != "decimal" {
+			return nil
+		}
+		numeric = true
+	}
+	if !numeric {
+		return nil
+	}
+
+	// Exclusive bounds take precedence over min and max, see addValidations
+	lower, lowerName := validation.ExclusiveMin.Or(validation.Min), boundName(validation.ExclusiveMin, "exclusiveMinimum", "min")
+	upper, upperName := validation.ExclusiveMax.Or(validation.Max), boundName(validation.ExclusiveMax, "exclusiveMaximum", "max")
+	if lower.Exists && kindBound.Max.Exists &&
+		(lower.Val > kindBound.Max.Val || (validation.ExclusiveMin.Exists && lower.Val == kindBound.Max.Val)) {
+		return fmt.Errorf("invalid [%v] value %v, the maximum value of %v is %v", lowerName, lower.Val, kind, kindBound.Max.Val)
+	}
+	if upper.Exists && kindBound.Min.Exists &&
+		(upper.Val < kindBound.Min.Val || (validation.ExclusiveMax.Exists && upper.Val == kindBound.Min.Val)) {
+		return fmt.Errorf("invalid [%v] value %v, the minimum value of %v is %v", upperName, upper.Val, kind, kindBound.Min.Val)
+	}
+
+	if kindBound.Min.Exists && (!lower.Exists || lower.Val < kindBound.Min.Val) {
+		validation.Min, validation.ExclusiveMin = kindBound.Min, WithVal[float64]{}
+	}
+	if kindBound.Max.Exists && (!upper.Exists || upper.Val > kindBound.Max.Val) {
+		validation.Max, validation.ExclusiveMax = kindBound.Max, WithVal[float64]{}
+	}
+	return nil
+}
+
+// Gets the name of the exclusive bound if it exists, otherwise the name of the inclusive one
+func boundName(exclusive WithVal[float64], exclusiveName, name string) string {
+	if exclusive.Exists {
+		return exclusiveName
+	}
+	return name
+}
+
+// Adds the length of fixed length arrays as minItems and maxItems validations if they are not already set
+func addArrayBounds(typ reflect.Type, types []string, validation *Validation) {
+	if typ.Kind() != reflect.Array || !tags.Contains(types, "array") {
+		return
+	}
+
+	if !validation.MinItems.Exists && !validation.Min.Exists {
+		validation.MinItems = CreateVal(typ.Len())
+	}
+	if !validation.MaxItems.Exists && !validation.Max.Exists {
+		validation.MaxItems = CreateVal(typ.Len())
+	}
+}
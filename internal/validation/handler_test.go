@@ -3,15 +3,18 @@ package validation
 import (
 	"reflect"
 	"testing"
+
+	"github.com/s-augustovitko/mongo-schema-go/internal/tags"
+	"github.com/s-augustovitko/mongo-schema-go/pkg/schema/jsonschema"
 )
 
-type setValTest struct {
+type setOptionalTest struct {
 	arg  WithVal[string]
 	want bool
 }
 
-func TestSetVal(t *testing.T) {
-	tests := []setValTest{
+func TestSetOptional(t *testing.T) {
+	tests := []setOptionalTest{
 		{WithVal[string]{Val: "test", Exists: true}, true},
 		{WithVal[string]{Val: "", Exists: true}, true},
 		{WithVal[string]{Val: "test", Exists: false}, false},
@@ -19,15 +22,14 @@ func TestSetVal(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		obj := BsonM{}
-		test.arg.SetVal("field", &obj)
+		have := jsonschema.Optional[string]{}
+		test.arg.setOptional(&have)
 
-		have, ok := obj["field"]
-		if ok != test.want {
-			t.Errorf("\nGot: %#v;\nWant: %#v", ok, test.want)
+		if have.Exists != test.want {
+			t.Errorf("\nGot: %#v;\nWant: %#v", have.Exists, test.want)
 		}
-		if ok && have != test.arg.Val {
-			t.Errorf("\nGot: %#v;\nWant: %#v", have, test.arg.Val)
+		if have.Exists && have.Val != test.arg.Val {
+			t.Errorf("\nGot: %#v;\nWant: %#v", have.Val, test.arg.Val)
 		}
 	}
 }
@@ -49,7 +51,7 @@ func TestAddValidations(t *testing.T) {
 		{[]string{"string"}, Validation{PatternProps: CreateVal("gi")},
 			BsonM{}},
 		{[]string{"string"}, Validation{Pattern: CreateVal("@gmail.com$"), PatternProps: CreateVal("gi")},
-			BsonM{"pattern": "@gmail.com$", "patternProperties": "gi"}},
+			BsonM{"pattern": "@gmail.com$"}},
 		{[]string{"int"}, Validation{Min: CreateVal[float64](1), Max: CreateVal[float64](20), MultipleOf: CreateVal[float64](4)},
 			BsonM{"maximum": float64(20), "minimum": float64(1), "multipleOf": float64(4)}},
 		{[]string{"long"}, Validation{Min: CreateVal(1.1), Max: CreateVal(20.4), MultipleOf: CreateVal(4.2)},
@@ -62,13 +64,23 @@ func TestAddValidations(t *testing.T) {
 			BsonM{"maxItems": 20, "minItems": 1, "uniqueItems": true}},
 		{[]string{"object"}, Validation{Min: CreateVal(1.1), Max: CreateVal(20.4)},
 			BsonM{"maxProperties": 20, "minProperties": 1}},
+		{[]string{"int"}, Validation{ExclusiveMin: CreateVal[float64](0), ExclusiveMax: CreateVal[float64](10)},
+			BsonM{"maximum": float64(10), "minimum": float64(0), "exclusiveMaximum": true, "exclusiveMinimum": true}},
+		{[]string{"double"}, Validation{Min: CreateVal(1.5), ExclusiveMin: CreateVal(2.5), Max: CreateVal(5.5)},
+			BsonM{"maximum": 5.5, "minimum": 2.5, "exclusiveMinimum": true}},
+		{[]string{"string"}, Validation{ExclusiveMin: CreateVal[float64](1)},
+			BsonM{}},
+		{[]string{"string", "array"}, Validation{MinLength: CreateVal(1), MaxLength: CreateVal(20), MaxItems: CreateVal(5)},
+			BsonM{"maxLength": 20, "minLength": 1, "maxItems": 5, "uniqueItems": false}},
+		{[]string{"string", "array", "object"}, Validation{Min: CreateVal[float64](1), Max: CreateVal[float64](10), MinItems: CreateVal(2), MaxProperties: CreateVal(3)},
+			BsonM{"maxLength": 10, "minLength": 1, "maxItems": 10, "minItems": 2, "uniqueItems": false, "maxProperties": 3, "minProperties": 1}},
 	}
 
 	for _, test := range tests {
-		have := BsonM{}
-		addValidations(test.arg1, test.arg2, &have)
+		schema := &jsonschema.Schema{}
+		addValidations(test.arg1, test.arg2, schema)
 
-		if !reflect.DeepEqual(have, test.want) {
+		if have := schema.ToBsonM(); !reflect.DeepEqual(have, test.want) {
 			t.Errorf("\nGot: %#v;\nWant: %#v;", have, test.want)
 		}
 	}
@@ -104,6 +116,32 @@ func TestParseValidation(t *testing.T) {
 		{"pattern=@gmail.com$", Validation{Pattern: CreateVal("@gmail.com$")}, false},
 		{"patternProperties", Validation{}, true},
 		{"patternProperties=gi", Validation{PatternProps: CreateVal("gi")}, false},
+		{"exclusiveMinimum=1.5", Validation{ExclusiveMin: CreateVal(1.5)}, false},
+		{"exclusiveMaximum=-2", Validation{ExclusiveMax: CreateVal[float64](-2)}, false},
+		{"exclusiveMinimum=asd", Validation{}, true},
+		{"exclusiveMinimum=5,exclusiveMaximum=1", Validation{ExclusiveMin: CreateVal[float64](5), ExclusiveMax: CreateVal[float64](1)}, true},
+		{"exclusiveMinimum=1,exclusiveMaximum=1", Validation{ExclusiveMin: CreateVal[float64](1), ExclusiveMax: CreateVal[float64](1)}, true},
+		{"min=1,exclusiveMaximum=1", Validation{Min: CreateVal[float64](1), ExclusiveMax: CreateVal[float64](1)}, true},
+		{"min=1,exclusiveMaximum=2", Validation{Min: CreateVal[float64](1), ExclusiveMax: CreateVal[float64](2)}, false},
+		{"exclusiveMinimum=3,max=2", Validation{ExclusiveMin: CreateVal[float64](3), Max: CreateVal[float64](2)}, true},
+		{"min=1,exclusiveMinimum=2", Validation{Min: CreateVal[float64](1), ExclusiveMin: CreateVal[float64](2)}, true},
+		{"max=5,exclusiveMaximum=4", Validation{Max: CreateVal[float64](5), ExclusiveMax: CreateVal[float64](4)}, true},
+		{"minLength=5,maxLength=4", Validation{MinLength: CreateVal(5), MaxLength: CreateVal(4)}, true},
+		{"minLength=1,maxLength=20", Validation{MinLength: CreateVal(1), MaxLength: CreateVal(20)}, false},
+		{"minLength=1.5", Validation{}, true},
+		{"maxLength=-1", Validation{}, true},
+		{"minItems=3,maxItems=2", Validation{MinItems: CreateVal(3), MaxItems: CreateVal(2)}, true},
+		{"minItems=0,maxItems=0", Validation{MinItems: CreateVal(0), MaxItems: CreateVal(0)}, false},
+		{"minProperties=1,maxProperties=4", Validation{MinProperties: CreateVal(1), MaxProperties: CreateVal(4)}, false},
+		{"minProperties=5,maxProperties=4", Validation{MinProperties: CreateVal(5), MaxProperties: CreateVal(4)}, true},
+		{"maxProperties", Validation{}, true},
+		{"pattern=^[a-z]{1,5}$,min=1", Validation{Pattern: CreateVal("^[a-z]{1,5}$"), Min: CreateVal[float64](1)}, false},
+		{"pattern=^a=b$", Validation{Pattern: CreateVal("^a=b$")}, false},
+		{"pattern='^[,]$', required", Validation{Pattern: CreateVal("^[,]$"), Required: true}, false},
+		{"pattern=''", Validation{Pattern: CreateVal("")}, false},
+		{"pattern=", Validation{}, true},
+		{"pattern=^[a-z", Validation{}, true},
+		{"min=1,pattern='a", Validation{}, true},
 	}
 
 	for _, test := range tests {
@@ -114,3 +152,80 @@ func TestParseValidation(t *testing.T) {
 		}
 	}
 }
+
+type checkValidationTest struct {
+	arg  string
+	want string
+}
+
+func TestCheckValidation(t *testing.T) {
+	tests := []checkValidationTest{
+		{"min=1,exclusiveMinimum=2", "invalid [validation] tag at offset 6: invalid [min,exclusiveMinimum] values, can not be used together"},
+		{"exclusiveMaximum=4, max=5", "invalid [validation] tag at offset 20: invalid [max,exclusiveMaximum] values, can not be used together"},
+		{"max=1,min=2", "invalid [validation] tag at offset 6: invalid [min,max] values, min can not be larger than max"},
+		{"exclusiveMinimum=2,max=2", "invalid [validation] tag at offset 19: invalid [exclusiveMinimum,max] values, exclusiveMinimum must be lower than max"},
+		{"minItems=3,maxItems=2", "invalid [validation] tag at offset 11: invalid [minItems,maxItems] values, minItems can not be larger than maxItems"},
+	}
+
+	for _, test := range tests {
+		_, err := parseValidation(test.arg)
+		err = tags.WithTagName(tagValid, err)
+
+		if err == nil || err.Error() != test.want {
+			t.Errorf("\nGot: %#v;\nWant: %#v", err, test.want)
+		}
+	}
+}
+
+type addKindBoundsTest struct {
+	arg1    reflect.Kind
+	arg2    []string
+	arg3    Validation
+	want    Validation
+	wantErr bool
+}
+
+func TestAddKindBounds(t *testing.T) {
+	tests := []addKindBoundsTest{
+		{reflect.Int, []string{"int", "long"}, Validation{}, Validation{}, false},
+		{reflect.Int64, []string{"long"}, Validation{}, Validation{}, false},
+		{reflect.Float32, []string{"double"}, Validation{}, Validation{}, false},
+		{reflect.Int8, []string{"int"}, Validation{},
+			Validation{Min: CreateVal[float64](-128), Max: CreateVal[float64](127)}, false},
+		{reflect.Uint16, []string{"int", "long"}, Validation{},
+			Validation{Min: CreateVal[float64](0), Max: CreateVal[float64](65535)}, false},
+		{reflect.Uint32, []string{"long"}, Validation{Min: CreateVal[float64](10)},
+			Validation{Min: CreateVal[float64](10), Max: CreateVal[float64](4294967295)}, false},
+		{reflect.Uint8, []string{"int"}, Validation{Max: CreateVal[float64](300)},
+			Validation{Min: CreateVal[float64](0), Max: CreateVal[float64](255)}, false},
+		{reflect.Int8, []string{"int"}, Validation{Min: CreateVal[float64](-200), Max: CreateVal[float64](100)},
+			Validation{Min: CreateVal[float64](-128), Max: CreateVal[float64](100)}, false},
+		{reflect.Uint8, []string{"int"}, Validation{ExclusiveMin: CreateVal[float64](-1), ExclusiveMax: CreateVal[float64](255)},
+			Validation{Min: CreateVal[float64](0), ExclusiveMax: CreateVal[float64](255)}, false},
+		{reflect.Uint8, []string{"int"}, Validation{Min: CreateVal[float64](300)}, Validation{Min: CreateVal[float64](300)}, true},
+		{reflect.Uint8, []string{"int"}, Validation{ExclusiveMin: CreateVal[float64](255)}, Validation{ExclusiveMin: CreateVal[float64](255)}, true},
+		{reflect.Int16, []string{"int"}, Validation{Max: CreateVal[float64](-40000)}, Validation{Max: CreateVal[float64](-40000)}, true},
+		{reflect.Uint, []string{"long"}, Validation{ExclusiveMax: CreateVal[float64](0)}, Validation{ExclusiveMax: CreateVal[float64](0)}, true},
+		{reflect.Uint64, []string{"long"}, Validation{Max: CreateVal[float64](20)},
+			Validation{Min: CreateVal[float64](0), Max: CreateVal[float64](20)}, false},
+		{reflect.Uint, []string{"long", "string"}, Validation{}, Validation{}, false},
+		{reflect.Uint, []string{}, Validation{}, Validation{}, false},
+	}
+
+	for _, test := range tests {
+		// "null" of the Nullable option does not change the bounds
+		for _, types := range [][]string{test.arg2, append(append([]string{}, test.arg2...), "null")} {
+			have := test.arg3
+			err := addKindBounds(test.arg1, types, &have)
+
+			if !reflect.DeepEqual(have, test.want) || test.wantErr != (err != nil) {
+				t.Errorf("\nKind: %v, %v;\nGot: %#v;\nWant: %#v;\nErr: %#v", test.arg1, types, have, test.want, err)
+			}
+		}
+	}
+
+	have := Validation{}
+	if err := addKindBounds(reflect.Uint8, []string{"null"}, &have); err != nil || !reflect.DeepEqual(have, Validation{}) {
+		t.Errorf("\nGot: %#v;\nWant: %#v;\nErr: %#v", have, Validation{}, err)
+	}
+}
@@ -0,0 +1,139 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/s-augustovitko/mongo-schema-go/internal/tags"
+	"github.com/s-augustovitko/mongo-schema-go/pkg/schema/jsonschema"
+)
+
+// Configuration of the items of an array or the values of a map
+type itemsConfig struct {
+	BsonType []string
+	// Schema of a provider, nil if the items are reflected
+	Schema     *jsonschema.Schema
+	Validation Validation
+	Enum       WithVal[[]interface{}]
+}
+
+// Gets the configuration of the first level of items of the field
+func (cfg config) items() itemsConfig {
+	return itemsConfig{BsonType: cfg.ItemsBsonType, Schema: cfg.ItemsSchema, Validation: cfg.ItemsValidation, Enum: cfg.Enum}
+}
+
+// Gets the deepest level addressed by the items.N and itemsType.N tags (eg. items.2 are the items of the items)
+// the first level is addressed by the items and itemsType tags, so N starts at 2
+func nestedTagsDepth(field reflect.StructField) (int, string, error) {
+	depth, deepest := 1, ""
+	for _, key := range tags.Keys(string(field.Tag)) {
+		name, level, ok := strings.Cut(key, ".")
+		if !ok || (name != tagItems && name != tagItemsType) {
+			continue
+		}
+
+		n, err := strconv.Atoi(level)
+		if err != nil || n < 2 {
+			return depth, deepest, fmt.Errorf("invalid tag [%v], the depth must be a number greater than 1, use the [%v] tag for the first level", key, name)
+		}
+		if n > depth {
+			depth, deepest = n, key
+		}
+	}
+	return depth, deepest, nil
+}
+
+// Creates the configuration of the nested arrays and maps of the items of the field, from the items.N and itemsType.N tags
+// levels are created up to the deepest tag, or up to the deepest array or map when the field has an enum tag
+// since the enum applies to the deepest items
+func nestedItems(cfg config, item reflect.Type, field reflect.StructField, opts Options) ([]itemsConfig, error) {
+	maxDepth, deepest, err := nestedTagsDepth(field)
+	if err != nil {
+		return nil, err
+	}
+	hasEnum := field.Tag.Get(tagEnum) != ""
+
+	var out []itemsConfig
+	depth, typ, types, isSchema := 1, item, cfg.ItemsBsonType, cfg.ItemsSchema != nil
+	for !isSchema && isContainer(typ, types) && (depth < maxDepth || hasEnum) {
+		elem := indirectType(typ.Elem())
+		depth++
+		typeTag := field.Tag.Get(fmt.Sprintf("%v.%v", tagItemsType, depth))
+		// Map values of interface types can be anything unless the itemsType tag is set
+		if typ.Kind() == reflect.Map && isAnyInterface(elem) && typeTag == "" {
+			break
+		}
+
+		level := itemsConfig{}
+		if provider, ok := getProvider(elem); ok {
+			level.Schema, level.BsonType, err = providedSchema(typeTag, provider)
+		} else {
+			level.BsonType, err = getType(typeTag, elem, opts)
+		}
+		if err != nil {
+			return out, tags.WithTagName(fmt.Sprintf("%v.%v", tagItemsType, depth), err)
+		}
+		if hasOption(field, "minsize") && typeTag == "" {
+			level.BsonType = minSizeTypes(elem.Kind(), level.BsonType)
+		}
+		level.BsonType = nullableItems(typ.Elem(), level.BsonType, level.Schema, opts)
+		level.Validation, err = parseValidation(field.Tag.Get(fmt.Sprintf("%v.%v", tagItems, depth)))
+		if err != nil {
+			return out, tags.WithTagName(fmt.Sprintf("%v.%v", tagItems, depth), err)
+		}
+		if typeTag == "" && level.Schema == nil {
+			if err := addKindBounds(elem.Kind(), level.BsonType, &level.Validation); err != nil {
+				return out, err
+			}
+			addArrayBounds(elem, level.BsonType, &level.Validation)
+		}
+		level.Enum, err = parseEnum("", elem, level.BsonType)
+		if err != nil {
+			return out, err
+		}
+
+		out = append(out, level)
+		typ, types, isSchema = elem, level.BsonType, level.Schema != nil
+	}
+
+	if depth < maxDepth {
+		return out, fmt.Errorf("the [%v] tag is deeper than the nested arrays and maps of the field", deepest)
+	}
+	return out, nil
+}
+
+// Checks if typ is an array or a map that is described with its items
+func isContainer(typ reflect.Type, types []string) bool {
+	switch typ.Kind() {
+	case reflect.Array, reflect.Slice:
+		return tags.Contains(types, "array")
+	case reflect.Map:
+		return tags.Contains(types, "object")
+	}
+	return false
+}
+
+// Creates the schema of the items of an array or the values of a map with its configuration
+// nested are the configurations of the next levels, levels without configuration are created from their types
+func itemsSchema(items itemsConfig, nested []itemsConfig, typ reflect.Type, opts Options, w walk) (*jsonschema.Schema, []error) {
+	if items.Schema != nil {
+		schema := items.Schema
+		addEnum(items.Enum, schema)
+		addValidations(items.BsonType, items.Validation, schema)
+		return schema, []error{}
+	}
+
+	schema, errs := createTypeSchema(typ, items.BsonType, items.Validation, nested, opts, w)
+	addEnum(items.Enum, schema)
+	return schema, errs
+}
+
+// Creates the schema of the elements of an array or map type
+func elemSchema(typ reflect.Type, nested []itemsConfig, opts Options, w walk) (*jsonschema.Schema, []error) {
+	if len(nested) == 0 {
+		return typeSchema(typ, opts, w)
+	}
+	return itemsSchema(nested[0], nested[1:], indirectType(typ), opts, w)
+}
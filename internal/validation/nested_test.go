@@ -0,0 +1,80 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/s-augustovitko/mongo-schema-go/internal/tags"
+)
+
+type nestedTestItem struct {
+	Name string `bson:"name"`
+}
+
+type nestedTest struct {
+	Matrix   [][]int                     `bson:"matrix" validation:"max=3" items:"min=1" items.2:"max=10" itemsType.2:"int"`
+	Tags     []map[string]string         `bson:"tags" items.2:"max=20" enum:"a,b"`
+	Groups   [][]nestedTestItem          `bson:"groups" items.2:"min=1"`
+	Deep     map[string][][]string       `bson:"deep" items.3:"pattern=^[a-z]+$" enum:"x,y"`
+	Plain    [][]string                  `bson:"plain"`
+	Any      []map[string]interface{}    `bson:"any" itemsType.2:"objectId"`
+	Invalid  [][]int                     `bson:"invalid" items.3:"min=1"`
+	Invalid2 []int                       `bson:"invalid2" items.1:"min=1"`
+	Invalid3 [][]int                     `bson:"invalid3" itemsType.2:"unknown"`
+	Invalid4 [][]string                  `bson:"invalid4" items.2:"min=a"`
+	Invalid5 [][]int                     `bson:"invalid5" enum:"a"`
+	Invalid6 []map[string]nestedTestItem `bson:"invalid6" items.3:"min=1"`
+}
+
+func TestCreateJSONSchemaNested(t *testing.T) {
+	want := BsonM{
+		"matrix": BsonM{"bsonType": []string{"array"}, "maxItems": 3, "uniqueItems": false,
+			"items": BsonM{"bsonType": []string{"array"}, "minItems": 1, "uniqueItems": false,
+				"items": BsonM{"bsonType": []string{"int"}, "maximum": float64(10)}}},
+		"tags": BsonM{"bsonType": []string{"array"}, "uniqueItems": false,
+			"items": BsonM{"bsonType": []string{"object"},
+				"additionalProperties": BsonM{"bsonType": []string{"string"}, "maxLength": 20, "enum": []interface{}{"a", "b"}}}},
+		"groups": BsonM{"bsonType": []string{"array"}, "uniqueItems": false,
+			"items": BsonM{"bsonType": []string{"array"}, "uniqueItems": false,
+				"items": BsonM{"bsonType": []string{"object"}, "minProperties": 1,
+					"properties": BsonM{"name": BsonM{"bsonType": []string{"string"}}}, "required": []string{}}}},
+		"deep": BsonM{"bsonType": []string{"object"},
+			"additionalProperties": BsonM{"bsonType": []string{"array"}, "uniqueItems": false,
+				"items": BsonM{"bsonType": []string{"array"}, "uniqueItems": false,
+					"items": BsonM{"bsonType": []string{"string"}, "pattern": "^[a-z]+$", "enum": []interface{}{"x", "y"}}}}},
+		"plain": BsonM{"bsonType": []string{"array"}, "uniqueItems": false,
+			"items": BsonM{"bsonType": []string{"array"}, "uniqueItems": false,
+				"items": BsonM{"bsonType": []string{"string"}}}},
+		"any": BsonM{"bsonType": []string{"array"}, "uniqueItems": false,
+			"items": BsonM{"bsonType": []string{"object"},
+				"additionalProperties": BsonM{"bsonType": []string{"objectId"}}}},
+	}
+	wantErrs := []string{
+		"[invalid]: the [items.3] tag is deeper than the nested arrays and maps of the field",
+		"[invalid2]: invalid tag [items.1], the depth must be a number greater than 1, use the [items] tag for the first level",
+		"[invalid3]: the following types are invalid [unknown]",
+		"[invalid4]: invalid [items.2] tag at offset 0: invalid value of [min], strconv.ParseFloat: parsing \"a\": invalid syntax",
+		"[invalid5]: enum value [a] does not match the types [int long]",
+		"[invalid6]: the [items.3] tag is deeper than the nested arrays and maps of the field",
+	}
+
+	have, _, errs := createTestSchema(reflect.TypeOf(nestedTest{}), Options{})
+
+	if !reflect.DeepEqual(want, have) {
+		for k, v := range want {
+			if !reflect.DeepEqual(have[k], v) {
+				t.Errorf("Field: %v;\nGot: %#v;\nWant: %#v", k, have[k], v)
+			}
+		}
+		if len(want) != len(have) {
+			t.Errorf("Field: Object;\nGot: %#v;\nWant: %#v", have, want)
+		}
+	}
+	haveErrs := []string{}
+	for _, err := range errs {
+		haveErrs = append(haveErrs, err.Error())
+	}
+	if !tags.CompareArr(haveErrs, wantErrs) {
+		t.Errorf("Field: Errors;\nGot: %#v;\nWant: %#v", haveErrs, wantErrs)
+	}
+}
@@ -0,0 +1,131 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/s-augustovitko/mongo-schema-go/internal/tags"
+	"github.com/s-augustovitko/mongo-schema-go/pkg/schema/jsonschema"
+)
+
+const (
+	tagAdditionalProps = "additionalProperties"
+	tagTitle           = "title"
+)
+
+// Settings of an object schema, from the blank fields of a struct (eg. _ struct{} `title:"Address"`)
+// or from the tags of a field with a struct type, array of structs or map of structs
+type objectConfig struct {
+	Combinators []combinator
+	// bool or fragment
+	AdditionalProps WithVal[interface{}]
+	Title           WithVal[string]
+}
+
+// Checks if the object config changes the object schema
+func (cfg objectConfig) isEmpty() bool {
+	return len(cfg.Combinators) == 0 && !cfg.AdditionalProps.Exists && !cfg.Title.Exists
+}
+
+// Parses the additionalProperties and title tags of the field
+// additionalProperties can be true, false, the name of a registered fragment or a json object
+func parseObjectTags(field reflect.StructField) (objectConfig, error) {
+	cfg := objectConfig{}
+
+	title := field.Tag.Get(tagTitle)
+	if title != "" {
+		cfg.Title = CreateVal(title)
+	}
+
+	additional := field.Tag.Get(tagAdditionalProps)
+	if additional == "" {
+		return cfg, nil
+	}
+	items, err := tags.SplitNested(additional)
+	if err != nil {
+		return cfg, tags.WithTagName(tagAdditionalProps, err)
+	}
+	if len(items) != 1 {
+		return cfg, &tags.SyntaxError{Tag: tagAdditionalProps, Msg: fmt.Sprintf("expected one value, got %v", len(items))}
+	}
+
+	item := items[0]
+	switch {
+	case !item.Quoted && item.Val == "true":
+		cfg.AdditionalProps = CreateVal[interface{}](true)
+	case !item.Quoted && item.Val == "false":
+		cfg.AdditionalProps = CreateVal[interface{}](false)
+	default:
+		frag, err := parseFragment(item)
+		if err != nil {
+			return cfg, &tags.SyntaxError{Tag: tagAdditionalProps, Offset: item.Offset, Msg: err.Error()}
+		}
+		cfg.AdditionalProps = CreateVal[interface{}](frag)
+	}
+	return cfg, nil
+}
+
+// Gets the object config of a struct from its blank fields, blank fields can also have combinator tags
+func structObjectConfig(typ reflect.Type) (objectConfig, []error) {
+	out := objectConfig{}
+	errors := []error{}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Name != "_" {
+			continue
+		}
+
+		combinators, err := parseCombinators(field)
+		if err != nil {
+			errors = append(errors, createErrorWithTag(field.Name, field.Name, err))
+			continue
+		}
+		cfg, err := parseObjectTags(field)
+		if err != nil {
+			errors = append(errors, createErrorWithTag(field.Name, field.Name, err))
+			continue
+		}
+
+		out.Combinators = append(out.Combinators, combinators...)
+		out.AdditionalProps = cfg.AdditionalProps.Or(out.AdditionalProps)
+		out.Title = cfg.Title.Or(out.Title)
+	}
+	return out, errors
+}
+
+// Adds the object config to the object schema obj
+func addObjectConfig(cfg objectConfig, opts Options, w walk, obj *jsonschema.Schema) []error {
+	errors := addCombinators(cfg.Combinators, opts, w, obj)
+	cfg.Title.setOptional(&obj.Title)
+
+	if !cfg.AdditionalProps.Exists {
+		return errors
+	}
+	if frag, ok := cfg.AdditionalProps.Val.(fragment); ok {
+		schema, errs := fragmentSchema(frag, opts, w)
+		errors = append(errors, errs...)
+		obj.AdditionalProperties = &jsonschema.BoolOrSchema{Allow: true, Schema: schema}
+		return errors
+	}
+	obj.AdditionalProperties = &jsonschema.BoolOrSchema{Allow: cfg.AdditionalProps.Val.(bool)}
+	return errors
+}
+
+// Checks if the items of an array or values of a map are described as objects with properties
+func isStructItems(typ reflect.Type, cfg config) bool {
+	return indirectType(typ).Kind() == reflect.Struct && tags.Contains(cfg.ItemsBsonType, "object") && cfg.ItemsSchema == nil
+}
+
+// Allows _id in objects that restrict their additional properties (eg. additionalProperties false or an inline map)
+// the driver adds an objectId _id to the documents without one
+func AllowID(obj *jsonschema.Schema) {
+	additional := obj.AdditionalProperties
+	if additional == nil || (additional.Allow && additional.Schema == nil) {
+		return
+	}
+
+	if _, ok := obj.Properties["_id"]; obj.Properties == nil || ok {
+		return
+	}
+	obj.Properties["_id"] = &jsonschema.Schema{BsonType: []string{"objectId"}}
+}
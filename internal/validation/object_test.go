@@ -0,0 +1,162 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/s-augustovitko/mongo-schema-go/internal/tags"
+	"github.com/s-augustovitko/mongo-schema-go/pkg/schema/jsonschema"
+)
+
+type objectTestAddress struct {
+	_      struct{} `title:"Address" additionalProperties:"false"`
+	Street string   `bson:"street"`
+}
+
+type objectTestItem struct {
+	Name string `bson:"name"`
+}
+
+type objectTestStrict struct {
+	_     struct{} `additionalProperties:"false"`
+	Label string   `bson:"label"`
+}
+
+type objectTest struct {
+	Address  objectTestAddress          `bson:"address"`
+	Override objectTestAddress          `bson:"override" title:"Other" additionalProperties:"true"`
+	Item     objectTestItem             `bson:"item"`
+	Items    []objectTestItem           `bson:"items" title:"Item" additionalProperties:"{\"bsonType\": \"string\"}"`
+	ByName   map[string]*objectTestItem `bson:"byName" additionalProperties:"false"`
+	Strict   objectTestStrict           `bson:"strict"`
+	Invalid  string                     `bson:"invalid" title:"Invalid"`
+	Invalid2 objectTestItem             `bson:"invalid2" additionalProperties:"maybe"`
+	Invalid3 objectTestItem             `bson:"invalid3" additionalProperties:"true,false"`
+	Invalid4 []string                   `bson:"invalid4" additionalProperties:"false"`
+	Invalid5 map[string]string          `bson:",inline" additionalProperties:"false"`
+}
+
+func TestCreateJSONSchemaObject(t *testing.T) {
+	address := func(title string, additional bool) BsonM {
+		return BsonM{
+			"bsonType":             []string{"object"},
+			"title":                title,
+			"additionalProperties": additional,
+			"properties":           BsonM{"street": BsonM{"bsonType": []string{"string"}}},
+			"required":             []string{}}
+	}
+	strict := BsonM{
+		"bsonType":             []string{"object"},
+		"additionalProperties": false,
+		"properties":           BsonM{"label": BsonM{"bsonType": []string{"string"}}},
+		"required":             []string{}}
+
+	type objectTestCase struct {
+		arg  Options
+		want BsonM
+	}
+	tests := []objectTestCase{
+		{Options{}, BsonM{
+			"_id":      BsonM{"bsonType": []string{"objectId"}}, // Invalid5 is still an inline map
+			"address":  address("Address", false),
+			"override": address("Other", true),
+			"item": BsonM{
+				"bsonType":   []string{"object"},
+				"properties": BsonM{"name": BsonM{"bsonType": []string{"string"}}},
+				"required":   []string{}},
+			"items": BsonM{"bsonType": []string{"array"}, "uniqueItems": false, "items": BsonM{
+				"bsonType":             []string{"object"},
+				"title":                "Item",
+				"additionalProperties": BsonM{"bsonType": []string{"string"}},
+				"properties":           BsonM{"name": BsonM{"bsonType": []string{"string"}}},
+				"required":             []string{}}},
+			"byName": BsonM{"bsonType": []string{"object"}, "additionalProperties": BsonM{
+				"bsonType":             []string{"object"},
+				"additionalProperties": false,
+				"properties":           BsonM{"name": BsonM{"bsonType": []string{"string"}}},
+				"required":             []string{}}},
+			"strict":   strict,
+			"invalid":  BsonM{"bsonType": []string{"string"}},
+			"invalid4": BsonM{"bsonType": []string{"array"}, "items": BsonM{"bsonType": []string{"string"}}, "uniqueItems": false},
+		}},
+		{Options{AdditionalProperties: CreateVal(false)}, BsonM{
+			"_id":      BsonM{"bsonType": []string{"objectId"}}, // Invalid5 is still an inline map
+			"address":  address("Address", false),
+			"override": address("Other", true),
+			"item": BsonM{
+				"bsonType":             []string{"object"},
+				"additionalProperties": false,
+				"properties":           BsonM{"name": BsonM{"bsonType": []string{"string"}}},
+				"required":             []string{}},
+			"items": BsonM{"bsonType": []string{"array"}, "uniqueItems": false, "items": BsonM{
+				"bsonType":             []string{"object"},
+				"title":                "Item",
+				"additionalProperties": BsonM{"bsonType": []string{"string"}},
+				"properties":           BsonM{"name": BsonM{"bsonType": []string{"string"}}},
+				"required":             []string{}}},
+			"byName": BsonM{"bsonType": []string{"object"}, "additionalProperties": BsonM{
+				"bsonType":             []string{"object"},
+				"additionalProperties": false,
+				"properties":           BsonM{"name": BsonM{"bsonType": []string{"string"}}},
+				"required":             []string{}}},
+			"strict":   strict,
+			"invalid":  BsonM{"bsonType": []string{"string"}},
+			"invalid4": BsonM{"bsonType": []string{"array"}, "items": BsonM{"bsonType": []string{"string"}}, "uniqueItems": false},
+		}},
+	}
+	wantErrs := []string{
+		"[invalid]: the additionalProperties and title tags can only be used with structs, arrays of structs or maps of structs",
+		"[invalid2]: invalid [additionalProperties] tag at offset 0: fragment [maybe] is not registered",
+		"[invalid3]: invalid [additionalProperties] tag at offset 0: expected one value, got 2",
+		"[invalid4]: the additionalProperties and title tags can only be used with structs, arrays of structs or maps of structs",
+		"[invalid5]: the additionalProperties and title tags can only be used with structs, arrays of structs or maps of structs",
+	}
+
+	for _, test := range tests {
+		haveSchema := &jsonschema.Schema{}
+		errs := CreateJSONSchema(reflect.TypeOf(objectTest{}), test.arg, haveSchema)
+		have := haveSchema.ToBsonM()
+
+		if !reflect.DeepEqual(have["properties"], test.want) {
+			t.Errorf("Field: Object;\nGot: %#v;\nWant: %#v;", have["properties"], test.want)
+		}
+		haveErrs := []string{}
+		for _, err := range errs {
+			haveErrs = append(haveErrs, err.Error())
+		}
+		if !tags.CompareArr(haveErrs, wantErrs) {
+			t.Errorf("Field: Errors;\nGot: %#v;\nWant: %#v", haveErrs, wantErrs)
+		}
+	}
+}
+
+type allowIDTest struct {
+	arg  BsonM
+	want BsonM
+}
+
+func TestAllowID(t *testing.T) {
+	id := BsonM{"bsonType": []string{"objectId"}}
+	tests := []allowIDTest{
+		{BsonM{"properties": BsonM{}},
+			BsonM{"properties": BsonM{}}},
+		{BsonM{"additionalProperties": true, "properties": BsonM{}},
+			BsonM{"additionalProperties": true, "properties": BsonM{}}},
+		{BsonM{"additionalProperties": false, "properties": BsonM{}},
+			BsonM{"additionalProperties": false, "properties": BsonM{"_id": id}}},
+		{BsonM{"additionalProperties": BsonM{"bsonType": "string"}, "properties": BsonM{}},
+			BsonM{"additionalProperties": BsonM{"bsonType": []string{"string"}}, "properties": BsonM{"_id": id}}},
+		{BsonM{"additionalProperties": false, "properties": BsonM{"_id": BsonM{"bsonType": []string{"string"}}}},
+			BsonM{"additionalProperties": false, "properties": BsonM{"_id": BsonM{"bsonType": []string{"string"}}}}},
+		{BsonM{"additionalProperties": false},
+			BsonM{"additionalProperties": false}},
+	}
+
+	for _, test := range tests {
+		schema, _ := jsonschema.Parse(test.arg)
+		AllowID(schema)
+		if have := schema.ToBsonM(); !reflect.DeepEqual(have, test.want) {
+			t.Errorf("\nGot: %#v;\nWant: %#v", have, test.want)
+		}
+	}
+}
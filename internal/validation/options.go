@@ -0,0 +1,20 @@
+package validation
+
+import "github.com/s-augustovitko/mongo-schema-go/internal/tags"
+
+// Options used while creating the json schema
+type Options struct {
+	NumericPolicy tags.NumericMode
+	// Names of the fields that do not have a name in their tags
+	NamingPolicy tags.NamingMode
+	// Adds the "null" type to pointers, slices, maps and omitempty fields
+	Nullable bool
+	// Fields without omitempty are required, since the driver always writes them
+	InferRequired bool
+	// Times a recursive type is expanded, with 0 recursive fields are skipped with a warning
+	MaxRecursion int
+	// Value of additionalProperties of the nested objects that do not set it
+	AdditionalProperties WithVal[bool]
+	// Overrides of the fields by path, nil if there are none
+	Overrides *Overrides
+}
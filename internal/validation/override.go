@@ -0,0 +1,237 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/s-augustovitko/mongo-schema-go/internal/tags"
+	"github.com/s-augustovitko/mongo-schema-go/pkg/schema/jsonschema"
+)
+
+// Override of a field of a type that can not be tagged, found by its dotted path from the root struct (eg. "address.zip")
+type Override struct {
+	// Replaces the schema of the field, nil if it is not replaced
+	Schema *jsonschema.Schema
+	// Struct tags merged into the tags of the field, they take precedence over the tags of the field
+	// the validation and items tags are added to the ones of the field like Validation and Items
+	Tags string
+	// Validations added to the validation and items tags of the field, they take precedence over the ones of the field
+	Validation string
+	Items      string
+}
+
+// Overrides by path, keeps track of the paths found while walking the struct
+type Overrides struct {
+	items map[string]Override
+	found map[string]bool
+}
+
+func NewOverrides(items map[string]Override) *Overrides {
+	return &Overrides{items: items, found: map[string]bool{}}
+}
+
+// Gets the override of the path and marks it as found
+func (o *Overrides) lookup(path string) (Override, bool) {
+	if o == nil {
+		return Override{}, false
+	}
+
+	item, ok := o.items[path]
+	if ok {
+		o.found[path] = true
+	}
+	return item, ok
+}
+
+// Gets the sorted paths that were not found while walking the struct
+func (o *Overrides) Unknown() []string {
+	out := []string{}
+	if o == nil {
+		return out
+	}
+
+	for path := range o.items {
+		if !o.found[path] {
+			out = append(out, path)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Checks the syntax of the tags and validations of the override, so invalid rules are found before the struct is walked
+// the enum values are converted to the bson types of the field when it is walked
+func (o Override) Check() error {
+	if _, err := mergeTags("", o.Tags); err != nil {
+		return err
+	}
+
+	tag := reflect.StructTag(o.Tags)
+	for _, key := range []string{tagType, tagItemsType} {
+		if val := tag.Get(key); val != "" {
+			if _, err := tags.ParseTypes(val); err != nil {
+				return tags.WithTagName(key, err)
+			}
+		}
+	}
+	if _, err := tags.SplitList(tag.Get(tagEnum)); err != nil {
+		return tags.WithTagName(tagEnum, err)
+	}
+	for _, item := range [][2]string{{tagValid, tag.Get(tagValid)}, {tagItems, tag.Get(tagItems)}, {tagValid, o.Validation}, {tagItems, o.Items}} {
+		if _, err := parseValidation(item[1]); err != nil {
+			return tags.WithTagName(item[0], err)
+		}
+	}
+	return nil
+}
+
+// Applies the override to the tags of the field
+func (o Override) apply(tag reflect.StructTag) (reflect.StructTag, error) {
+	var err error
+	if o.Tags != "" {
+		if tag, err = mergeTags(tag, o.Tags); err != nil {
+			return tag, err
+		}
+	}
+
+	added := []string{}
+	for _, item := range [][2]string{{tagValid, o.Validation}, {tagItems, o.Items}} {
+		if item[1] != "" {
+			added = append(added, item[0]+":"+strconv.Quote(item[1]))
+		}
+	}
+	if len(added) == 0 {
+		return tag, nil
+	}
+	return mergeTags(tag, strings.Join(added, " "))
+}
+
+// Applies the parts of the override (each tag, the validations and the items) that can be applied to the field one by one
+// the parts that can not be applied are skipped, so the field keeps its own tags for them, and their errors are returned
+func (o Override) applyParts(typ reflect.Type, field reflect.StructField, opts Options) (reflect.StructTag, []error) {
+	parts := []Override{}
+	keys, _ := tags.StructTagKeys(o.Tags)
+	seen := map[string]bool{}
+	for _, key := range keys {
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		val, _ := reflect.StructTag(o.Tags).Lookup(key)
+		parts = append(parts, Override{Tags: key + ":" + strconv.Quote(val)})
+	}
+	if o.Validation != "" {
+		parts = append(parts, Override{Validation: o.Validation})
+	}
+	if o.Items != "" {
+		parts = append(parts, Override{Items: o.Items})
+	}
+
+	errors := []error{}
+	for _, part := range parts {
+		tag, err := part.apply(field.Tag)
+		if err == nil {
+			partField := field
+			partField.Tag = tag
+			if _, err = createConfig(typ, partField, opts); err == nil {
+				field.Tag = tag
+				continue
+			}
+		}
+		errors = append(errors, err)
+	}
+	return field.Tag, errors
+}
+
+// Merges struct tags into the tags of a field the same way overrides do, used to join the tags of the same path
+func MergeTags(tag, override string) (string, error) {
+	out, err := mergeTags(reflect.StructTag(tag), override)
+	return string(out), err
+}
+
+// Merges the override tags into the tags of the field, keys of the override replace the ones of the field
+// except for the validation and items tags, which are merged by validation
+func mergeTags(tag reflect.StructTag, override string) (reflect.StructTag, error) {
+	overrideKeys, err := tags.StructTagKeys(override)
+	if err != nil {
+		return tag, tags.WithTagName("override", err)
+	}
+
+	items := []string{}
+	seen := map[string]bool{}
+	for _, key := range overrideKeys {
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		val, ok := reflect.StructTag(override).Lookup(key)
+		if !ok {
+			return tag, fmt.Errorf("invalid [override] tag, the value of [%v] is not a valid quoted string, backslashes must be escaped (eg. \\\\d)", key)
+		}
+		if current := tag.Get(key); current != "" && (key == tagValid || key == tagItems) {
+			val = mergeValidations(current, val)
+		}
+		items = append(items, key+":"+strconv.Quote(val))
+	}
+	for _, key := range tags.Keys(string(tag)) {
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		val, _ := tag.Lookup(key)
+		items = append(items, key+":"+strconv.Quote(val))
+	}
+	return reflect.StructTag(strings.Join(items, " ")), nil
+}
+
+// Bounds of the same side, an override of one of them replaces the other one of the field
+var sameSideBounds = map[string]string{
+	"min":              "exclusiveMinimum",
+	"exclusiveMinimum": "min",
+	"max":              "exclusiveMaximum",
+	"exclusiveMaximum": "max",
+}
+
+// Adds the validations of the override to the ones of the field, the validations of the field that the override sets are replaced
+// so the bounds do not contradict each other
+func mergeValidations(current, override string) string {
+	if kept := replacedValidations(current, override); kept != "" {
+		return kept + "," + override
+	}
+	return override
+}
+
+// Removes the validations of the field that are set by the override, including the bounds of the same side
+// invalid validations are kept as they are, so their errors are returned when the tag is parsed
+func replacedValidations(current, override string) string {
+	currentItems, err := tags.SplitKeyValues(current)
+	if err != nil {
+		return current
+	}
+	overrideItems, err := tags.SplitKeyValues(override)
+	if err != nil {
+		return current
+	}
+
+	replaced := map[string]bool{}
+	for _, item := range overrideItems {
+		replaced[item.Key] = true
+		replaced[sameSideBounds[item.Key]] = true
+	}
+
+	kept := []string{}
+	for i, item := range currentItems {
+		if replaced[item.Key] {
+			continue
+		}
+		end := len(current)
+		if i+1 < len(currentItems) {
+			end = currentItems[i+1].Offset
+		}
+		kept = append(kept, strings.TrimSuffix(strings.TrimSpace(current[item.Offset:end]), ","))
+	}
+	return strings.Join(kept, ",")
+}
@@ -0,0 +1,208 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/s-augustovitko/mongo-schema-go/internal/tags"
+	"github.com/s-augustovitko/mongo-schema-go/pkg/schema/jsonschema"
+)
+
+type overrideTestAddress struct {
+	Street string `bson:"street"`
+	Zip    string `bson:"zip"`
+}
+
+type overrideTest struct {
+	Home     overrideTestAddress   `bson:"home" validation:"required"`
+	Work     overrideTestAddress   `bson:"work"`
+	History  []overrideTestAddress `bson:"history"`
+	Events   chan int              `bson:"events"`
+	Invalid  string                `bson:"invalid"`
+	Invalid2 string                `bson:"invalid2"`
+	Skipped  string                `bson:"-"`
+}
+
+func TestCreateJSONSchemaOverrides(t *testing.T) {
+	overrides := NewOverrides(map[string]Override{
+		"home.zip":       {Tags: `validation:"required,pattern=^\\d{5}$" description:"Zip code"`},
+		"work":           {Schema: &jsonschema.Schema{BsonType: []string{"string"}}},
+		"history.street": {Tags: `bson:"line1" validation:"max=64"`},
+		"events":         {Schema: &jsonschema.Schema{BsonType: []string{"int"}}},
+		"invalid":        {Tags: `validation:"min=1`},
+		"invalid2":       {Tags: `validation:"pattern=^\d$"`},
+		"unknown.path":   {Tags: `validation:"required"`},
+		"skipped":        {Tags: `validation:"required"`},
+		"work.zip":       {Tags: `validation:"required"`},
+	})
+	address := func(street, zip BsonM, required ...string) BsonM {
+		return BsonM{"bsonType": []string{"object"}, "properties": BsonM{"street": street, "zip": zip}, "required": required}
+	}
+	want := BsonM{
+		"home": address(BsonM{"bsonType": []string{"string"}},
+			BsonM{"bsonType": []string{"string"}, "pattern": "^\\d{5}$", "description": "Zip code"}, "zip"),
+		"work": BsonM{"bsonType": []string{"string"}},
+		"history": BsonM{"bsonType": []string{"array"}, "uniqueItems": false, "items": BsonM{
+			"bsonType": []string{"object"},
+			"properties": BsonM{
+				"line1": BsonM{"bsonType": []string{"string"}, "maxLength": 64},
+				"zip":   BsonM{"bsonType": []string{"string"}}},
+			"required": []string{}}},
+		"events":   BsonM{"bsonType": []string{"int"}},
+		"invalid":  BsonM{"bsonType": []string{"string"}},
+		"invalid2": BsonM{"bsonType": []string{"string"}},
+	}
+	wantErrs := []string{
+		"[invalid]: invalid [override] tag at offset 11: unterminated quote",
+		"[invalid2]: invalid [override] tag, the value of [validation] is not a valid quoted string, backslashes must be escaped (eg. \\\\d)",
+	}
+
+	have, required, errs := createTestSchema(reflect.TypeOf(overrideTest{}), Options{Overrides: overrides})
+
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("Field: Object;\nGot: %#v;\nWant: %#v", have, want)
+	}
+	if !tags.CompareArr(required, []string{"home"}) {
+		t.Errorf("Field: Required;\nGot: %#v;\nWant: %#v", required, []string{"home"})
+	}
+	haveErrs := []string{}
+	for _, err := range errs {
+		haveErrs = append(haveErrs, err.Error())
+	}
+	if !tags.CompareArr(haveErrs, wantErrs) {
+		t.Errorf("Field: Errors;\nGot: %#v;\nWant: %#v", haveErrs, wantErrs)
+	}
+	if unknown, want := overrides.Unknown(), []string{"skipped", "unknown.path", "work.zip"}; !tags.CompareArr(unknown, want) {
+		t.Errorf("Field: Unknown;\nGot: %#v;\nWant: %#v", unknown, want)
+	}
+}
+
+type mergeTagsTest struct {
+	arg1 reflect.StructTag
+	arg2 string
+	want reflect.StructTag
+}
+
+func TestMergeTags(t *testing.T) {
+	tests := []mergeTagsTest{
+		{`bson:"a"`, `validation:"required"`, `validation:"required" bson:"a"`},
+		{`bson:"a" validation:"min=1"`, `validation:"max=2"`, `validation:"min=1,max=2" bson:"a"`},
+		{`bson:"a" validation:"min=1,max=5"`, `validation:"exclusiveMinimum=0" description:"x"`, `validation:"max=5,exclusiveMinimum=0" description:"x" bson:"a"`},
+		{`items:"required,min=1"`, `items:"min=2"`, `items:"required,min=2"`},
+		{`bson:"a"`, `enum:"x" enum:"y"`, `enum:"x" bson:"a"`},
+		{`validation:"pattern=\"a\""`, `bson:"b"`, `bson:"b" validation:"pattern=\"a\""`},
+		{`bson:"a"`, `validation:"pattern=^\\d$"`, `validation:"pattern=^\\d$" bson:"a"`},
+	}
+
+	for _, test := range tests {
+		if have, err := mergeTags(test.arg1, test.arg2); err != nil || have != test.want {
+			t.Errorf("\nGot: %#v;\nWant: %#v;\nError: %v", have, test.want, err)
+		}
+	}
+}
+
+type overrideApplyTest struct {
+	arg1 Override
+	arg2 reflect.StructTag
+	want reflect.StructTag
+}
+
+func TestOverrideApply(t *testing.T) {
+	tests := []overrideApplyTest{
+		{Override{}, `bson:"a"`, `bson:"a"`},
+		{Override{Validation: "max=5"}, `bson:"a" validation:"required,min=1"`, `validation:"required,min=1,max=5" bson:"a"`},
+		{Override{Validation: "max=5", Items: "min=1"}, `bson:"a"`, `validation:"max=5" items:"min=1" bson:"a"`},
+		{Override{Tags: `validation:"min=2" enum:"x"`, Validation: "max=5"}, `bson:"a" validation:"required"`,
+			`validation:"required,min=2,max=5" enum:"x" bson:"a"`},
+		{Override{Tags: `validation:"max=5"`}, `validation:"required,min=1"`, `validation:"required,min=1,max=5"`},
+		{Override{Validation: "exclusiveMinimum=2"}, `validation:"min=1, max=5,required"`, `validation:"max=5,required,exclusiveMinimum=2"`},
+		{Override{Validation: "max=3,pattern='a,b'"}, `validation:"pattern='x,y',exclusiveMaximum=5,min=1"`, `validation:"min=1,max=3,pattern='a,b'"`},
+	}
+
+	for _, test := range tests {
+		if have, err := test.arg1.apply(test.arg2); err != nil || have != test.want {
+			t.Errorf("\nGot: %#v;\nWant: %#v;\nError: %v", have, test.want, err)
+		}
+	}
+}
+
+type overrideTestCount struct {
+	Count int `bson:"count" validation:"max=10"`
+}
+
+// Fields keep their own tags when the override can not be applied to them
+func TestCreateJSONSchemaOverridesFallback(t *testing.T) {
+	overrides := NewOverrides(map[string]Override{
+		"home.zip":    {Validation: "bogus=1"},
+		"home.street": {Tags: `type:"unknown"`},
+		"unknown":     {Validation: "max=5"},
+	})
+	want := BsonM{"bsonType": []string{"object"}, "required": []string{}, "properties": BsonM{
+		"street": BsonM{"bsonType": []string{"string"}},
+		"zip":    BsonM{"bsonType": []string{"string"}}}}
+	wantErrs := []string{
+		"[home.street]: the following types are invalid [unknown]",
+		"[home.zip]: invalid [validation] tag at offset 0: invalid validation [bogus]",
+		"[events]: type [chan] is not supported",
+	}
+
+	have, _, errs := createTestSchema(reflect.TypeOf(overrideTest{}), Options{Overrides: overrides})
+
+	if !reflect.DeepEqual(have["home"], want) {
+		t.Errorf("Field: Home;\nGot: %#v;\nWant: %#v", have["home"], want)
+	}
+	haveErrs := []string{}
+	for _, err := range errs {
+		haveErrs = append(haveErrs, err.Error())
+	}
+	if !tags.CompareArr(haveErrs, wantErrs) {
+		t.Errorf("Field: Errors;\nGot: %#v;\nWant: %#v", haveErrs, wantErrs)
+	}
+	if unknown := overrides.Unknown(); !tags.CompareArr(unknown, []string{"unknown"}) {
+		t.Errorf("Field: Unknown;\nGot: %#v;\nWant: %#v", unknown, []string{"unknown"})
+	}
+
+	// Only the parts of the override that can not be applied are skipped
+	overrides = NewOverrides(map[string]Override{
+		"count": {Tags: `enum:"1,'2',null,true" description:"Count"`, Validation: "exclusiveMinimum=0"},
+	})
+	want = BsonM{
+		"count": BsonM{"bsonType": []string{"int", "long"}, "description": "Count", "exclusiveMinimum": true, "minimum": float64(0), "maximum": float64(10)},
+	}
+	wantErrs = []string{"count"}
+
+	have, _, errs = createTestSchema(reflect.TypeOf(overrideTestCount{}), Options{Overrides: overrides})
+
+	if !reflect.DeepEqual(have, want) || !tags.CompareArr(errorPaths(errs), wantErrs) {
+		t.Errorf("\nGot: %#v;\nWant: %#v;\nErrs: %#v", have, want, errs)
+	}
+}
+
+type overrideCheckTest struct {
+	arg  Override
+	want string
+}
+
+func TestOverrideCheck(t *testing.T) {
+	tests := []overrideCheckTest{
+		{Override{}, ""},
+		{Override{Tags: `enum:"'a,b',c" type:"string,null" itemsType:"int"`, Validation: "required,max=5", Items: "min=1"}, ""},
+		{Override{Validation: "bogus=1"}, "invalid [validation] tag at offset 0: invalid validation [bogus]"},
+		{Override{Items: "min=a"}, "invalid [items] tag at offset 0: invalid value of [min], strconv.ParseFloat: parsing \"a\": invalid syntax"},
+		{Override{Tags: `validation:"min=1,exclusiveMinimum=2"`}, "invalid [validation] tag at offset 6: invalid [min,exclusiveMinimum] values, can not be used together"},
+		{Override{Tags: `type:"string,unknown"`}, "the following types are invalid [unknown]"},
+		{Override{Tags: `itemsType:"1"`}, "the following types are invalid [1]"},
+		{Override{Tags: `enum:"'a"`}, "invalid [enum] tag at offset 0: unterminated quote"},
+		{Override{Tags: `enum:"a`}, "invalid [override] tag at offset 5: unterminated quote"},
+	}
+
+	for _, test := range tests {
+		have := ""
+		if err := test.arg.Check(); err != nil {
+			have = err.Error()
+		}
+		if have != test.want {
+			t.Errorf("\nGot: %#v;\nWant: %#v", have, test.want)
+		}
+	}
+}
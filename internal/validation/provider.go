@@ -0,0 +1,49 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/s-augustovitko/mongo-schema-go/internal/tags"
+	"github.com/s-augustovitko/mongo-schema-go/pkg/schema/jsonschema"
+)
+
+// Implemented by types that describe their own schema instead of being reflected
+// the method can have a value or a pointer receiver
+type SchemaProvider interface {
+	MongoSchema() map[string]interface{}
+}
+
+var providerType = reflect.TypeOf((*SchemaProvider)(nil)).Elem()
+
+// Gets the SchemaProvider of typ if typ or *typ implements it
+func getProvider(typ reflect.Type) (SchemaProvider, bool) {
+	if typ.Kind() == reflect.Interface {
+		return nil, false
+	}
+	if !typ.Implements(providerType) && !reflect.PointerTo(typ).Implements(providerType) {
+		return nil, false
+	}
+
+	provider, ok := reflect.New(typ).Interface().(SchemaProvider)
+	return provider, ok
+}
+
+// Gets the parsed schema of the provider and its bson types, parsing copies the schema so later changes do not reach the provider
+// if the type tag is not empty it replaces the bsonType of the schema
+func providedSchema(typeTag string, provider SchemaProvider) (*jsonschema.Schema, []string, error) {
+	schema, err := jsonschema.Parse(provider.MongoSchema())
+	if err != nil {
+		return &jsonschema.Schema{}, []string{}, fmt.Errorf("invalid schema of [%T], %v", provider, err)
+	}
+
+	types, err := tags.ParseTypes(typeTag)
+	if err != nil {
+		return schema, types, err
+	}
+	if len(types) > 0 {
+		schema.BsonType = types
+		return schema, append([]string{}, types...), nil
+	}
+	return schema, append([]string{}, schema.BsonType...), nil
+}
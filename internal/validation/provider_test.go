@@ -0,0 +1,151 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/s-augustovitko/mongo-schema-go/internal/tags"
+)
+
+type testMoney struct {
+	Amount   int64
+	Currency string
+}
+
+func (testMoney) MongoSchema() map[string]interface{} {
+	return BsonM{"bsonType": "object", "required": []string{"amount", "currency"}}
+}
+
+type testGeoPoint struct {
+	Lng, Lat float64
+}
+
+func (*testGeoPoint) MongoSchema() map[string]interface{} {
+	return BsonM{"bsonType": []interface{}{"array"}, "minItems": 2, "maxItems": 2}
+}
+
+type testLevel uint8
+
+func (testLevel) MongoSchema() map[string]interface{} {
+	return BsonM{"bsonType": "int", "minimum": uint8(0), "maximum": int8(3), "enum": []int{0, 1, 2, 3}}
+}
+
+type testBlob []byte
+
+func (testBlob) MongoSchema() map[string]interface{} {
+	return BsonM{"bsonType": 1}
+}
+
+var testAddressSchema = BsonM{
+	"bsonType":   "object",
+	"properties": BsonM{"zip": BsonM{"bsonType": "string"}},
+	"required":   []string{"zip"},
+}
+
+type testAddress struct{}
+
+func (testAddress) MongoSchema() map[string]interface{} {
+	return testAddressSchema
+}
+
+type getProviderTest struct {
+	arg  reflect.Type
+	want bool
+}
+
+func TestGetProvider(t *testing.T) {
+	tests := []getProviderTest{
+		{reflect.TypeOf(testMoney{}), true},
+		{reflect.TypeOf(testGeoPoint{}), true},
+		{reflect.TypeOf(createConfigTestItem{}), false},
+		{reflect.TypeOf(""), false},
+		{reflect.TypeOf((*SchemaProvider)(nil)).Elem(), false},
+	}
+
+	for _, test := range tests {
+		if _, have := getProvider(test.arg); have != test.want {
+			t.Errorf("\nType: %v;\nGot: %#v;\nWant: %#v", test.arg, have, test.want)
+		}
+	}
+}
+
+type providedSchemaTest struct {
+	arg1      string
+	arg2      SchemaProvider
+	want      BsonM
+	wantTypes []string
+	wantErr   bool
+}
+
+func TestProvidedSchema(t *testing.T) {
+	tests := []providedSchemaTest{
+		{"", testMoney{}, BsonM{"bsonType": []string{"object"}, "required": []string{"amount", "currency"}}, []string{"object"}, false},
+		{"object,null", testMoney{}, BsonM{"bsonType": []string{"object", "null"}, "required": []string{"amount", "currency"}}, []string{"object", "null"}, false},
+		{"invalid", testMoney{}, BsonM{"bsonType": []string{"object"}, "required": []string{"amount", "currency"}}, []string{}, true},
+		{"", &testGeoPoint{}, BsonM{"bsonType": []string{"array"}, "minItems": 2, "maxItems": 2}, []string{"array"}, false},
+		{"", testLevel(0), BsonM{"bsonType": []string{"int"}, "minimum": 0.0, "maximum": 3.0, "enum": []interface{}{0, 1, 2, 3}}, []string{"int"}, false},
+		{"", testBlob{}, BsonM{}, []string{}, true},
+	}
+
+	for _, test := range tests {
+		schema, haveTypes, err := providedSchema(test.arg1, test.arg2)
+		have := schema.ToBsonM()
+
+		if !reflect.DeepEqual(have, test.want) || !tags.CompareArr(haveTypes, test.wantTypes) || test.wantErr != (err != nil) {
+			t.Errorf("\nGot: %#v, %#v;\nWant: %#v, %#v;\nErr: %#v", have, haveTypes, test.want, test.wantTypes, err)
+		}
+	}
+
+	if have := (testMoney{}).MongoSchema(); have["bsonType"] != "object" {
+		t.Errorf("\nProvided schema was modified: %#v", have)
+	}
+
+	have, _, _ := providedSchema("", testAddress{})
+	have.Properties["zip"].BsonType[0] = "null"
+	have.Required[0] = "other"
+	want := BsonM{
+		"bsonType":   "object",
+		"properties": BsonM{"zip": BsonM{"bsonType": "string"}},
+		"required":   []string{"zip"},
+	}
+	if !reflect.DeepEqual(testAddressSchema, want) {
+		t.Errorf("\nNested provided schema was modified;\nGot: %#v;\nWant: %#v", testAddressSchema, want)
+	}
+}
+
+type createJSONSchemaTestProvider struct {
+	Price    testMoney     `validation:"required,max=2" description:"Price of the item"`
+	Location *testGeoPoint `type:"array,null"`
+	History  []testMoney   `validation:"min=1" items:"min=2" description:"Previous prices"`
+	Blob     testBlob
+}
+
+func TestCreateJSONSchemaProvider(t *testing.T) {
+	want := BsonM{
+		"price": BsonM{
+			"bsonType":      []string{"object"},
+			"required":      []string{"amount", "currency"},
+			"maxProperties": 2,
+			"description":   "Price of the item"},
+		"location": BsonM{"bsonType": []string{"array", "null"}, "minItems": 2, "maxItems": 2, "uniqueItems": false},
+		"history": BsonM{
+			"bsonType": []string{"array"},
+			"items": BsonM{
+				"bsonType":      []string{"object"},
+				"required":      []string{"amount", "currency"},
+				"minProperties": 2,
+				"description":   "Previous prices"},
+			"minItems":    1,
+			"uniqueItems": false},
+	}
+	wantReq := []string{"price"}
+
+	have, required, errs := createTestSchema(reflect.TypeOf(createJSONSchemaTestProvider{}), Options{})
+
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("Field: Object;\nGot: %#v;\nWant: %#v;", have, want)
+	}
+	if !tags.CompareArr(required, wantReq) || len(errs) != 1 || errs[0].(ErrorWithTag).Tag() != "blob" {
+		t.Errorf("Field: Required;\nGot: %#v;\nWant: %#v;\nErrs: %#v", required, wantReq, errs)
+	}
+}
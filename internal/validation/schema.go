@@ -5,6 +5,7 @@ import (
 	"reflect"
 
 	"github.com/s-augustovitko/mongo-schema-go/internal/tags"
+	"github.com/s-augustovitko/mongo-schema-go/pkg/schema/jsonschema"
 )
 
 const (
@@ -19,167 +20,575 @@ const (
 )
 
 type config struct {
-	Validation      Validation
-	Tag             string
-	BsonType        []string
-	Enum            WithVal[[]string]
-	Description     WithVal[string]
+	Validation  Validation
+	Tag         string
+	BsonType    []string
+	Enum        WithVal[[]interface{}]
+	Description WithVal[string]
+	// Schemas of providers, nil if the field or its items are reflected
+	Schema          *jsonschema.Schema
 	ItemsBsonType   []string
+	ItemsSchema     *jsonschema.Schema
 	ItemsValidation Validation
+	Combinators     []combinator
+	Object          objectConfig
+	Tuple           []fragment
+	Nested          []itemsConfig
 	IsArray         bool
-	IsArrayOfStruct bool
+	IsMap           bool
 	IsStruct        bool
 	IsInline        bool
 }
 
 // Creates the configuration used for the json schema parsing
-// config is created based on the reflect value of a field
-func createConfig(value reflect.Value, field reflect.StructField) (config, error) {
+// config is created based on the type of a field, pointers must already be resolved
+func createConfig(typ reflect.Type, field reflect.StructField, opts Options) (config, error) {
 	var err error
 	cfg := config{}
 
 	// FIELD
-	cfg.IsStruct = value.Kind() == reflect.Struct
-	cfg.Tag, cfg.IsInline = tags.GetTag(field.Tag.Get(tagField), field.Tag.Get(tagBson), field.Name)
-	cfg.IsInline = cfg.IsStruct && cfg.IsInline // IsInline can only be true if the field is a struct
+	cfg.Tag, cfg.IsInline = tags.GetTagWithPolicy(field.Tag.Get(tagField), field.Tag.Get(tagBson), field.Name, opts.NamingPolicy)
 	// DESCRIPTION
 	description := field.Tag.Get(tagDesc)
 	if description != "" {
 		cfg.Description = CreateVal(description)
 	}
-	// ENUM
-	enum := tags.SplitTrim(field.Tag.Get(tagEnum), ",")
-	if len(enum) > 0 {
-		cfg.Enum = CreateVal(enum)
-	}
 	// TYPE
-	cfg.BsonType, err = tags.GetType(field.Tag.Get(tagType), value.Kind())
+	if provider, ok := getProvider(typ); ok {
+		cfg.Schema, cfg.BsonType, err = providedSchema(field.Tag.Get(tagType), provider)
+	} else {
+		cfg.BsonType, err = getType(field.Tag.Get(tagType), typ, opts)
+	}
+	if err != nil {
+		return cfg, tags.WithTagName(tagType, err)
+	}
+	// COMBINATORS AND OBJECT TAGS
+	cfg.Combinators, err = parseCombinators(field)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Object, err = parseObjectTags(field)
+	if err != nil {
+		return cfg, err
+	}
+	// Interface fields described by their combinators can be of any type
+	if typ.Kind() == reflect.Interface && field.Tag.Get(tagType) == "" && len(cfg.Combinators) > 0 {
+		cfg.BsonType = []string{}
+	}
+	// STRUCTS, ARRAYS AND MAPS
+	// Structs that are not objects (eg. time.Time or a type tag without "object") or provide their own schema are not walked
+	cfg.IsStruct = typ.Kind() == reflect.Struct && tags.Contains(cfg.BsonType, "object") && cfg.Schema == nil
+	cfg.IsArray = (typ.Kind() == reflect.Array || typ.Kind() == reflect.Slice) && tags.Contains(cfg.BsonType, "array") && cfg.Schema == nil
+	cfg.IsMap = typ.Kind() == reflect.Map && tags.Contains(cfg.BsonType, "object") && cfg.Schema == nil
+	cfg.IsInline = (cfg.IsStruct || cfg.IsMap) && cfg.IsInline // IsInline can only be true if the field is a struct or a map
+	// TUPLE
+	cfg.Tuple, err = parseTuple(field)
 	if err != nil {
 		return cfg, err
 	}
+	if len(cfg.Tuple) > 0 {
+		if err := checkTuple(cfg, typ, field); err != nil {
+			return cfg, err
+		}
+	}
+	// MINSIZE
+	if hasOption(field, "minsize") && field.Tag.Get(tagType) == "" {
+		cfg.BsonType = minSizeTypes(typ.Kind(), cfg.BsonType)
+	}
+	// NULLABLE
+	if opts.Nullable && !cfg.IsInline && len(cfg.BsonType) > 0 && isNullable(field) && !tags.Contains(cfg.BsonType, "null") {
+		cfg.BsonType = append(cfg.BsonType, "null")
+		if cfg.Schema != nil {
+			cfg.Schema.BsonType = cfg.BsonType
+			if cfg.Schema.Enum != nil {
+				cfg.Schema.Enum = NullableEnum(cfg.Schema.Enum, cfg.BsonType)
+			}
+		}
+	}
 	// VALIDATION
 	cfg.Validation, err = parseValidation(field.Tag.Get(tagValid))
-	cfg.Validation.Required = !cfg.IsInline && cfg.Validation.Required // Required can not be set if it is inline
 	if err != nil {
-		return cfg, err
+		return cfg, tags.WithTagName(tagValid, err)
 	}
-
-	// STRUCTS AND ARRAYS
-	cfg.IsArray = value.Kind() == reflect.Array || value.Kind() == reflect.Slice
-	if !cfg.IsArray {
-		return cfg, nil
+	// Fields without omitempty are always written by the driver
+	cfg.Validation.Required = cfg.Validation.Required || (opts.InferRequired && !hasOption(field, "omitempty"))
+	cfg.Validation.Required = !cfg.IsInline && cfg.Validation.Required // Required can not be set if it is inline
+	if field.Tag.Get(tagType) == "" && cfg.Schema == nil {
+		if err := addKindBounds(typ.Kind(), cfg.BsonType, &cfg.Validation); err != nil {
+			return cfg, err
+		}
+		addArrayBounds(typ, cfg.BsonType, &cfg.Validation)
+	}
+	// Tuples are shorter when the last items are optional, eg. with validation:"min=2"
+	if len(cfg.Tuple) > 0 && !cfg.Validation.MinItems.Exists && !cfg.Validation.Min.Exists {
+		cfg.Validation.MinItems = CreateVal(len(cfg.Tuple))
 	}
 
-	// ARRAYS
-	if value.Len() < 1 {
-		return cfg, fmt.Errorf("could not properly parse the slice since its empty")
+	// ARRAYS AND MAPS
+	if len(cfg.Tuple) > 0 {
+		return cfg, nil
 	}
-	item := value.Index(0)
-	if item.Kind() == reflect.Pointer {
-		item = item.Elem()
+	if !cfg.IsArray && !cfg.IsMap {
+		// ENUM
+		cfg.Enum, err = parseEnum(field.Tag.Get(tagEnum), typ, cfg.BsonType)
+		return cfg, tags.WithTagName(tagEnum, err)
 	}
-
-	// ARRAY OF STRUCTS
-	if item.Kind() == reflect.Struct {
-		cfg.IsArrayOfStruct = true
+	item := indirectType(typ.Elem())
+	// Map values of interface types can be anything unless the itemsType tag is set or they are a union
+	if cfg.IsMap && isAnyInterface(item) && field.Tag.Get(tagItemsType) == "" {
 		return cfg, nil
 	}
 
-	// ARRAYS
-	cfg.ItemsBsonType, err = tags.GetType(field.Tag.Get(tagItemsType), item.Kind())
+	// ITEMS (array items and map values)
+	if provider, ok := getProvider(item); ok {
+		cfg.ItemsSchema, cfg.ItemsBsonType, err = providedSchema(field.Tag.Get(tagItemsType), provider)
+	} else {
+		cfg.ItemsBsonType, err = getType(field.Tag.Get(tagItemsType), item, opts)
+	}
 	if err != nil {
-		return cfg, err
+		return cfg, tags.WithTagName(tagItemsType, err)
+	}
+	if hasOption(field, "minsize") && field.Tag.Get(tagItemsType) == "" {
+		cfg.ItemsBsonType = minSizeTypes(item.Kind(), cfg.ItemsBsonType)
 	}
+	cfg.ItemsBsonType = nullableItems(typ.Elem(), cfg.ItemsBsonType, cfg.ItemsSchema, opts)
 	cfg.ItemsValidation, err = parseValidation(field.Tag.Get(tagItems))
 	if err != nil {
+		return cfg, tags.WithTagName(tagItems, err)
+	}
+	if field.Tag.Get(tagItemsType) == "" && cfg.ItemsSchema == nil {
+		if err := addKindBounds(item.Kind(), cfg.ItemsBsonType, &cfg.ItemsValidation); err != nil {
+			return cfg, err
+		}
+		addArrayBounds(item, cfg.ItemsBsonType, &cfg.ItemsValidation)
+	}
+	// NESTED ITEMS (items of the items, eg. items.2 and itemsType.2)
+	cfg.Nested, err = nestedItems(cfg, item, field, opts)
+	if err != nil {
+		return cfg, err
+	}
+	// ENUM (applies to the deepest items)
+	if len(cfg.Nested) > 0 {
+		deepest := &cfg.Nested[len(cfg.Nested)-1]
+		deepest.Enum, err = parseEnum(field.Tag.Get(tagEnum), deepestType(item, len(cfg.Nested)), deepest.BsonType)
+		if err != nil {
+			return cfg, tags.WithTagName(tagEnum, err)
+		}
+		cfg.Enum, err = parseEnum("", item, cfg.ItemsBsonType)
 		return cfg, err
 	}
+	cfg.Enum, err = parseEnum(field.Tag.Get(tagEnum), item, cfg.ItemsBsonType)
+	return cfg, tags.WithTagName(tagEnum, err)
+}
+
+// Gets the type of the elements depth levels below typ
+func deepestType(typ reflect.Type, depth int) reflect.Type {
+	for i := 0; i < depth; i++ {
+		typ = indirectType(typ.Elem())
+	}
+	return typ
+}
+
+// Parses the enum tag with the values coerced to the bson types they describe
+// without an enum tag the values registered for typ are used
+func parseEnum(enumTag string, typ reflect.Type, types []string) (WithVal[[]interface{}], error) {
+	var enum []interface{}
+	var err error
+	if enumTag != "" {
+		enum, err = tags.ParseEnum(enumTag, types)
+	} else {
+		enum, err = tags.GetEnum(typ, types)
+	}
+	if err != nil || len(enum) == 0 {
+		return WithVal[[]interface{}]{}, err
+	}
+	return CreateVal(NullableEnum(enum, types)), nil
+}
+
+// Adds nil to the enum if the types include "null", since the enum is checked on its own and would reject null values
+// an empty enum is returned as it is, the enum is copied when nil is added
+func NullableEnum(enum []interface{}, types []string) []interface{} {
+	if len(enum) == 0 || !tags.Contains(types, "null") {
+		return enum
+	}
+	for _, item := range enum {
+		if item == nil {
+			return enum
+		}
+	}
+	return append(append([]interface{}{}, enum...), nil)
+}
+
+// Adds "null" to the bson types of array items and map values that are pointers or interfaces, since the driver writes null for nil items
+// elem is the type of the items before the pointers are resolved, the schema of a provider gets the same types
+func nullableItems(elem reflect.Type, types []string, schema *jsonschema.Schema, opts Options) []string {
+	if !opts.Nullable || (elem.Kind() != reflect.Pointer && elem.Kind() != reflect.Interface) || len(types) == 0 || tags.Contains(types, "null") {
+		return types
+	}
+
+	types = append(append([]string{}, types...), "null")
+	if schema != nil {
+		schema.BsonType = types
+		schema.Enum = NullableEnum(schema.Enum, types)
+	}
+	return types
+}
+
+// Checks if the driver can write null for the field (pointers, interfaces, slices and maps) or if it is omitempty
+func isNullable(field reflect.StructField) bool {
+	switch field.Type.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+		return true
+	}
+	return hasOption(field, "omitempty")
+}
+
+// Checks if the field or bson tag of the field have the option
+func hasOption(field reflect.StructField, option string) bool {
+	return tags.HasOption(field.Tag.Get(tagField), option) || tags.HasOption(field.Tag.Get(tagBson), option)
+}
+
+// With minsize the driver writes 64 bit integers as "int" when the value fits in 32 bits
+func minSizeTypes(kind reflect.Kind, types []string) []string {
+	switch kind {
+	case reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if tags.Contains(types, "long") && !tags.Contains(types, "int") {
+			return append([]string{"int"}, types...)
+		}
+	}
+	return types
+}
+
+// Checks if typ is an interface without a union, so its values can be anything
+func isAnyInterface(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Interface {
+		return false
+	}
+	_, ok := lookupUnion(typ)
+	return !ok
+}
 
-	return cfg, nil
+// Resolves pointer types to the type they point to
+func indirectType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	return typ
+}
+
+// Creates the json schema from the type of the struct
+// Arrays, pointers and nested structs are resolved through their types, so no sample values are needed
+// Fields of embedded and inline structs are promoted following the go rules, see structFields
+// Sets the properties and required fields of obj, inline maps also set its additionalProperties
+// Returns warnings.(ErrorWithTag)
+func CreateJSONSchema(typ reflect.Type, opts Options, obj *jsonschema.Schema) []error {
+	// Negative depths are the same as 0, otherwise the root itself would have no properties
+	if opts.MaxRecursion < 0 {
+		opts.MaxRecursion = 0
+	}
+	errors := createObjectSchema(typ, opts, walk{}, obj)
+	AllowID(obj)
+	return errors
 }
 
-// Creates the json schema from the reflect of the struct
-// Struct can be empty except for arrays, all arrays must be filled with at least 1 item of its kind
-// Returns an array of required fields.([]string) and warnings.(ErrorWithTag)
-func CreateJSONSchema(value reflect.Value, objProperties *BsonM) ([]string, []error) {
+// Creates the json schema of a struct at the position of the walk
+// recursive types are expanded up to opts.MaxRecursion times, after that the object has no properties
+func createObjectSchema(typ reflect.Type, opts Options, w walk, obj *jsonschema.Schema) []error {
+	if w.count(typ) > opts.MaxRecursion {
+		return []error{}
+	}
+	w = w.enter(typ)
+
+	objProperties := map[string]*jsonschema.Schema{}
 	requiredFields := []string{}
-	errors := []error{}
+	fields, errors := structFields(typ, opts)
+
+	// OBJECT CONFIG (blank fields)
+	objCfg, errs := structObjectConfig(typ)
+	errors = append(errors, errs...)
+	errors = append(errors, tagErrors("_", "_", addObjectConfig(objCfg, opts, w, obj))...)
+
+	for _, item := range fields {
+		field := item.Field
+		fieldTyp := indirectType(field.Type)
 
-	valTyp := value.Type()
-	for i := 0; i < value.NumField(); i++ {
-		val := value.Field(i)
-		field := valTyp.Field(i)
-		if val.Kind() == reflect.Pointer {
-			val = val.Elem()
+		// OVERRIDES
+		name, _ := tags.GetTagWithPolicy(field.Tag.Get(tagField), field.Tag.Get(tagBson), field.Name, opts.NamingPolicy)
+		override, hasOverride := opts.Overrides.lookup(w.field(name).String())
+		fieldTag := field.Tag
+		if hasOverride {
+			tag, err := override.apply(field.Tag)
+			if err != nil {
+				errors = append(errors, createErrorWithTag(name, field.Name, err))
+			} else {
+				field.Tag = tag
+			}
 		}
 
 		// CONFIG
-		cfg, err := createConfig(val, field)
+		cfg, err := createConfig(fieldTyp, field, opts)
+		// Only the parts of the override that can not be applied to the field are skipped, the field keeps its own tags for them
+		if err != nil && field.Tag != fieldTag && override.Schema == nil {
+			field.Tag = fieldTag
+			if _, fieldErr := createConfig(fieldTyp, field, opts); fieldErr != nil {
+				errors = append(errors, createErrorWithTag(cfg.Tag, field.Name, err))
+			} else {
+				var errs []error
+				field.Tag, errs = override.applyParts(fieldTyp, field, opts)
+				for _, err := range errs {
+					errors = append(errors, createErrorWithTag(name, field.Name, err))
+				}
+			}
+			cfg, err = createConfig(fieldTyp, field, opts)
+		}
+		// The schema of the override replaces the one of the field, even if the field can not be described (eg. a chan)
+		if hasOverride && override.Schema != nil {
+			if err == nil && cfg.Validation.Required {
+				requiredFields = append(requiredFields, cfg.Tag)
+			}
+			objProperties[cfg.Tag] = override.Schema.Copy()
+			continue
+		}
 		if err != nil {
 			errors = append(errors, createErrorWithTag(cfg.Tag, field.Name, err))
 			continue
 		}
+		fieldWalk := w.field(cfg.Tag)
+		// RECURSIVE TYPES
+		if opts.MaxRecursion == 0 && w.isRecursive(fieldTyp) {
+			err := fmt.Errorf("recursive type [%v] at [%v], $jsonSchema does not support references, set a maximum recursion to expand it", indirectType(fieldTyp), fieldWalk)
+			errors = append(errors, createErrorWithTag(cfg.Tag, field.Name, err))
+			continue
+		}
 
 		// BASE VALUES
-		obj := BsonM{"bsonType": cfg.BsonType}
-		addValidations(cfg.BsonType, cfg.Validation, &obj)
+		prop := &jsonschema.Schema{}
+		if len(cfg.BsonType) > 0 {
+			prop.BsonType = cfg.BsonType
+		}
+		if cfg.Schema != nil {
+			prop = cfg.Schema
+		}
+		addValidations(cfg.BsonType, cfg.Validation, prop)
+		errors = append(errors, tagErrors(cfg.Tag, field.Name, addCombinators(cfg.Combinators, opts, fieldWalk, prop))...)
 		if cfg.Validation.Required {
 			requiredFields = append(requiredFields, cfg.Tag)
 		}
 
-		// INLINE STRUCT
-		if cfg.IsStruct && cfg.IsInline {
-			props := BsonM{}
-			reqs, errs := CreateJSONSchema(val, &props)
+		// OBJECT TAGS
+		// Only structs, arrays of structs and maps of structs (not inline) are described as objects with properties
+		if !cfg.Object.isEmpty() && !cfg.IsStruct && !((cfg.IsArray || (cfg.IsMap && !cfg.IsInline)) && isStructItems(fieldTyp.Elem(), cfg)) {
+			err := fmt.Errorf("the additionalProperties and title tags can only be used with structs, arrays of structs or maps of structs")
+			errors = append(errors, createErrorWithTag(cfg.Tag, field.Name, err))
+			cfg.Object = objectConfig{}
+		}
+
+		// KEY PATTERN
+		// Only the keys of maps can be described with patternProperties
+		if cfg.Validation.PatternProps.Exists && !cfg.IsMap {
+			err := fmt.Errorf("the patternProperties validation can only be used with maps")
+			errors = append(errors, createErrorWithTag(cfg.Tag, field.Name, err))
+			cfg.Validation.PatternProps = WithVal[string]{}
+		}
 
-			for k, v := range props {
-				(*objProperties)[k] = v
+		// INLINE MAP
+		// Keys that are not properties of the struct are written from the map
+		if cfg.IsMap && cfg.IsInline {
+			if len(cfg.ItemsBsonType) == 0 && cfg.ItemsSchema == nil {
+				addKeyPattern(cfg.Validation, &jsonschema.Schema{}, obj)
+				continue
+			}
+
+			values, errs := createItemsSchema(cfg, indirectType(fieldTyp.Elem()), opts, w)
+			errors = append(errors, tagErrors(cfg.Tag, field.Name, errs)...)
+			if !addKeyPattern(cfg.Validation, values, obj) {
+				obj.AdditionalProperties = &jsonschema.BoolOrSchema{Allow: true, Schema: values}
 			}
-			requiredFields = append(requiredFields, reqs...)
-			errors = append(errors, errs...)
 			continue
 		}
 
 		// STRUCT
 		if cfg.IsStruct {
-			props := BsonM{}
-			reqs, errs := CreateJSONSchema(val, &props)
-			obj["properties"] = props
-			obj["required"] = reqs
+			errs := createObjectSchema(fieldTyp, opts, fieldWalk, prop)
 			errors = append(errors, errs...)
-			(*objProperties)[cfg.Tag] = obj
+			errors = append(errors, tagErrors(cfg.Tag, field.Name, addObjectConfig(cfg.Object, opts, fieldWalk, prop))...)
+			objProperties[cfg.Tag] = prop
 			continue
 		}
 
-		// ARRAY OF STRUCTS
-		if cfg.IsArrayOfStruct {
-			props := BsonM{}
-			reqs, errs := CreateJSONSchema(val.Index(0), &props)
-			obj["items"] = BsonM{
-				"bsonType":   []string{"object"},
-				"required":   reqs,
-				"properties": props,
-			}
-			errors = append(errors, errs...)
-			(*objProperties)[cfg.Tag] = obj
+		// TUPLE
+		if len(cfg.Tuple) > 0 {
+			errors = append(errors, tagErrors(cfg.Tag, field.Name, addTuple(cfg.Tuple, opts, fieldWalk, prop))...)
+			cfg.Description.setOptional(&prop.Description)
+			objProperties[cfg.Tag] = prop
 			continue
 		}
 
 		// ARRAY
 		if cfg.IsArray {
-			items := BsonM{"bsonType": cfg.ItemsBsonType}
-			cfg.Enum.SetVal("enum", &items)
-			addValidations(cfg.ItemsBsonType, cfg.ItemsValidation, &items)
-			cfg.Description.SetVal("description", &items)
+			items, errs := createItemsSchema(cfg, indirectType(fieldTyp.Elem()), opts, fieldWalk)
+			errors = append(errors, tagErrors(cfg.Tag, field.Name, errs)...)
+			errors = append(errors, tagErrors(cfg.Tag, field.Name, addObjectConfig(cfg.Object, opts, fieldWalk, items))...)
+			cfg.Description.setOptional(&items.Description)
+
+			prop.Items = items
+			objProperties[cfg.Tag] = prop
+			continue
+		}
+
+		// MAP
+		if cfg.IsMap {
+			cfg.Description.setOptional(&prop.Description)
+			if len(cfg.ItemsBsonType) == 0 && cfg.ItemsSchema == nil {
+				addKeyPattern(cfg.Validation, &jsonschema.Schema{}, prop)
+				objProperties[cfg.Tag] = prop
+				continue
+			}
+
+			values, errs := createItemsSchema(cfg, indirectType(fieldTyp.Elem()), opts, fieldWalk)
+			errors = append(errors, tagErrors(cfg.Tag, field.Name, errs)...)
+			errors = append(errors, tagErrors(cfg.Tag, field.Name, addObjectConfig(cfg.Object, opts, fieldWalk, values))...)
+			if !addKeyPattern(cfg.Validation, values, prop) {
+				prop.AdditionalProperties = &jsonschema.BoolOrSchema{Allow: true, Schema: values}
+			}
+			objProperties[cfg.Tag] = prop
+			continue
+		}
+
+		// UNION
+		// With combinator tags the union is added to allOf, so the value must be a variant and match the combinators
+		if fieldTyp.Kind() == reflect.Interface && cfg.Schema == nil {
+			_, hasUnion := lookupUnion(fieldTyp)
+			if hasUnion && len(cfg.Combinators) > 0 && (len(cfg.BsonType) == 0 || tags.Contains(cfg.BsonType, "object")) {
+				union := &jsonschema.Schema{BsonType: []string{"object"}}
+				// Without bson types the field allows null already, the union must allow it too with the Nullable option
+				if tags.Contains(cfg.BsonType, "null") || (opts.Nullable && len(cfg.BsonType) == 0) {
+					union.BsonType = append(union.BsonType, "null")
+				}
+				errors = append(errors, tagErrors(cfg.Tag, field.Name, addUnion(fieldTyp, opts, fieldWalk, union))...)
+				prop.AllOf = append(prop.AllOf, union)
+			} else if tags.Contains(cfg.BsonType, "object") {
+				errors = append(errors, tagErrors(cfg.Tag, field.Name, addUnion(fieldTyp, opts, fieldWalk, prop))...)
+			}
+		}
+
+		cfg.Description.setOptional(&prop.Description)
+		addEnum(cfg.Enum, prop)
+		objProperties[cfg.Tag] = prop
+	}
+
+	// Nested objects inherit the additionalProperties of the root
+	if obj.AdditionalProperties == nil && opts.AdditionalProperties.Exists {
+		obj.AdditionalProperties = &jsonschema.BoolOrSchema{Allow: opts.AdditionalProperties.Val}
+	}
+	obj.Properties = objProperties
+	obj.Required = requiredFields
+	return addErrorsPath(w.String(), errors)
+}
+
+// Creates the schema of the items of an array or the values of a map with the items configuration
+// struct items are walked and nested arrays or maps are created from the nested configuration or their types
+func createItemsSchema(cfg config, typ reflect.Type, opts Options, w walk) (*jsonschema.Schema, []error) {
+	return itemsSchema(cfg.items(), cfg.Nested, typ, opts, w)
+}
+
+// Creates the schema of a type with its bson types and validation already resolved
+// structs, arrays and maps are walked through their types, nested is the configuration of the next levels of items
+func createTypeSchema(typ reflect.Type, types []string, validation Validation, nested []itemsConfig, opts Options, w walk) (*jsonschema.Schema, []error) {
+	obj := &jsonschema.Schema{}
+	// Interfaces have no bson types, so any value is allowed
+	if len(types) > 0 {
+		obj.BsonType = types
+	}
+	addValidations(types, validation, obj)
+	errors := []error{}
+
+	switch {
+	case typ.Kind() == reflect.Struct && tags.Contains(types, "object"):
+		errs := createObjectSchema(typ, opts, w, obj)
+		errors = append(errors, errs...)
+	case (typ.Kind() == reflect.Array || typ.Kind() == reflect.Slice) && tags.Contains(types, "array"):
+		items, errs := elemSchema(typ.Elem(), nested, opts, w)
+		if !items.IsEmpty() {
+			obj.Items = items
+		}
+		errors = append(errors, errs...)
+	case typ.Kind() == reflect.Map && tags.Contains(types, "object"):
+		if len(nested) == 0 && isAnyInterface(indirectType(typ.Elem())) {
+			break
+		}
+		values, errs := elemSchema(typ.Elem(), nested, opts, w)
+		if !values.IsEmpty() {
+			obj.AdditionalProperties = &jsonschema.BoolOrSchema{Allow: true, Schema: values}
+		}
+		errors = append(errors, errs...)
+	case typ.Kind() == reflect.Interface && tags.Contains(types, "object"):
+		errors = append(errors, addUnion(typ, opts, w, obj)...)
+	}
+
+	return obj, errors
+}
 
-			obj["items"] = items
-		} else {
-			cfg.Description.SetVal("description", &obj)
-			cfg.Enum.SetVal("enum", &obj)
+// Creates the schema of a type that has no tags (eg. the items of a nested array)
+func typeSchema(typ reflect.Type, opts Options, w walk) (*jsonschema.Schema, []error) {
+	elem := typ
+	typ = indirectType(typ)
+	if provider, ok := getProvider(typ); ok {
+		schema, types, err := providedSchema("", provider)
+		if err != nil {
+			return schema, []error{err}
 		}
+		nullableItems(elem, types, schema, opts)
+		return schema, []error{}
+	}
+
+	types, err := getType("", typ, opts)
+	if err != nil {
+		return &jsonschema.Schema{}, []error{err}
+	}
+	types = nullableItems(elem, types, nil, opts)
+	validation := Validation{}
+	if err := addKindBounds(typ.Kind(), types, &validation); err != nil {
+		return &jsonschema.Schema{}, []error{err}
+	}
+	addArrayBounds(typ, types, &validation)
+	enum, err := parseEnum("", typ, types)
+	if err != nil {
+		return &jsonschema.Schema{}, []error{err}
+	}
 
-		(*objProperties)[cfg.Tag] = obj
+	obj, errs := createTypeSchema(typ, types, validation, nil, opts, w)
+	addEnum(enum, obj)
+	return obj, errs
+}
+
+// Sets the enum of the schema if it exists
+func addEnum(enum WithVal[[]interface{}], obj *jsonschema.Schema) {
+	if enum.Exists {
+		obj.Enum = enum.Val
+	}
+}
+
+// Adds the patternProperties validation as the key pattern of a map, values is the schema of each value
+// Returns false if there is no pattern, since keys that do not match the pattern are not allowed
+func addKeyPattern(validation Validation, values *jsonschema.Schema, obj *jsonschema.Schema) bool {
+	if !validation.PatternProps.Exists {
+		return false
 	}
 
-	return requiredFields, errors
+	obj.PatternProperties = map[string]*jsonschema.Schema{validation.PatternProps.Val: values}
+	obj.AdditionalProperties = &jsonschema.BoolOrSchema{Allow: false}
+	return true
+}
+
+// Adds the tag and name to errors that do not have them
+func tagErrors(tag, name string, errs []error) []error {
+	out := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if _, ok := err.(ErrorWithTag); !ok {
+			err = createErrorWithTag(tag, name, err)
+		}
+		out = append(out, err)
+	}
+	return out
 }
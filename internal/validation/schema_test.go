@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/s-augustovitko/mongo-schema-go/internal/tags"
+	"github.com/s-augustovitko/mongo-schema-go/pkg/schema/jsonschema"
 )
 
 type createConfigTestItem struct {
@@ -29,24 +30,18 @@ type createConfigTest struct {
 }
 
 func TestCreateConfig(t *testing.T) {
-	str := ""
-	obj := createConfigTest{
-		createConfigTestItem: createConfigTestItem{Arg8: []*string{&str}},
-		Obj:                  createConfigTestItem{Arg8: []*string{&str}},
-		Arr:                  []createConfigTestItem{{Arg8: []*string{&str}}},
-	}
 	wantArr := []config{
 		{
 			Validation: Validation{Min: CreateVal[float64](2), Max: CreateVal[float64](50)},
 			Tag:        "arg1",
 			BsonType:   []string{"string"},
-			Enum:       CreateVal([]string{"a", "b", "c", "d"}),
+			Enum:       CreateVal([]interface{}{"a", "b", "c", "d"}),
 		},
 		{
 			Validation: Validation{Required: true, Max: CreateVal[float64](5)},
 			Tag:        "_id",
-			BsonType:   []string{"objectId"},
-			Enum:       WithVal[[]string]{Val: nil},
+			BsonType:   []string{},
+			Enum:       WithVal[[]interface{}]{},
 		},
 		{
 			Validation: Validation{},
@@ -54,47 +49,46 @@ func TestCreateConfig(t *testing.T) {
 			BsonType:   []string{"object"},
 			IsInline:   true,
 			IsStruct:   true,
-			Enum:       WithVal[[]string]{Val: nil},
+			Enum:       WithVal[[]interface{}]{},
 		},
 		{
 			Validation: Validation{Required: true},
 			Tag:        "date",
 			BsonType:   []string{"date"},
-			Enum:       WithVal[[]string]{Val: nil},
+			Enum:       WithVal[[]interface{}]{},
 		},
 		{
-			Validation:      Validation{Required: true, Min: CreateVal[float64](1), Max: CreateVal[float64](5)},
-			IsArray:         true,
-			IsArrayOfStruct: true,
-			Tag:             "arr",
-			BsonType:        []string{"array"},
-			Enum:            WithVal[[]string]{Val: nil},
+			Validation:    Validation{Required: true, Min: CreateVal[float64](1), Max: CreateVal[float64](5)},
+			IsArray:       true,
+			Tag:           "arr",
+			BsonType:      []string{"array"},
+			ItemsBsonType: []string{"object"},
+			Enum:          WithVal[[]interface{}]{},
 		},
 		{
 			Validation: Validation{Required: true},
 			Tag:        "obj1",
 			BsonType:   []string{"object"},
 			IsStruct:   true,
-			Enum:       WithVal[[]string]{Val: nil},
+			Enum:       WithVal[[]interface{}]{},
 		},
 		{
 			Validation:  Validation{Required: true, Min: CreateVal[float64](1)},
 			BsonType:    []string{"object"},
+			IsMap:       true,
 			Tag:         "m",
 			Description: CreateVal("some cool description"),
-			Enum:        WithVal[[]string]{Val: nil},
+			Enum:        WithVal[[]interface{}]{},
 		},
 	}
 
-	val := reflect.ValueOf(obj)
-	typ := val.Type()
+	typ := reflect.TypeOf(createConfigTest{})
 
-	for i := 0; i < val.NumField(); i++ {
-		val := val.Field(i)
+	for i := 0; i < typ.NumField(); i++ {
 		field := typ.Field(i)
 		want := wantArr[i]
 
-		have, err := createConfig(val, field)
+		have, err := createConfig(indirectType(field.Type), field, Options{})
 
 		if !reflect.DeepEqual(have, want) || err != nil {
 			t.Errorf("Field:%#v;\nGot: %#v;\nWant: %#v;\nErr: %#v", have.Tag, have, want, err)
@@ -103,42 +97,36 @@ func TestCreateConfig(t *testing.T) {
 }
 
 func TestCreateJSONSchema(t *testing.T) {
-	str := ""
-	obj := createConfigTest{
-		createConfigTestItem: createConfigTestItem{Arg8: []*string{&str}},
-		Obj:                  createConfigTestItem{Arg8: []*string{&str}},
-		Arr:                  []createConfigTestItem{{Arg8: []*string{&str}}},
-	}
 	want := BsonM{
-		"_id":  BsonM{"bsonType": []string{"objectId"}},
-		"arg1": BsonM{"bsonType": []string{"string"}, "enum": []string{"a", "b", "c", "d"}, "maxLength": 50, "minLength": 2},
-		"arg2": BsonM{"bsonType": []string{"string"}, "pattern": "@gmail.com$", "patternProperties": "gi"},
+		"_id":  BsonM{},
+		"arg1": BsonM{"bsonType": []string{"string"}, "enum": []interface{}{"a", "b", "c", "d"}, "maxLength": 50, "minLength": 2},
+		"arg2": BsonM{"bsonType": []string{"string"}, "pattern": "@gmail.com$"},
 		"arg3": BsonM{"bsonType": []string{"int", "long"}, "multipleOf": 2.3},
 		"arg4": BsonM{"bsonType": []string{"long"}, "minimum": 2.1},
 		"arg5": BsonM{"bsonType": []string{"double"}, "multipleOf": 2.3},
-		"arg6": BsonM{"bsonType": []string{"decimal"}},
+		"arg6": BsonM{"bsonType": []string{"double"}},
 		"arg7": BsonM{"bsonType": []string{"bool"}},
 		"arg8": BsonM{
 			"bsonType": []string{"array"},
 			"items": BsonM{
 				"bsonType":  []string{"string"},
-				"enum":      []string{"a", "b", "c", "d"},
+				"enum":      []interface{}{"a", "b", "c", "d"},
 				"maxLength": 7, "minLength": 3},
 			"uniqueItems": true},
 		"arr": BsonM{
 			"bsonType": []string{"array"},
 			"items": BsonM{"bsonType": []string{"object"},
 				"properties": BsonM{
-					"arg2": BsonM{"bsonType": []string{"string"}, "pattern": "@gmail.com$", "patternProperties": "gi"},
+					"arg2": BsonM{"bsonType": []string{"string"}, "pattern": "@gmail.com$"},
 					"arg3": BsonM{"bsonType": []string{"int", "long"}, "multipleOf": 2.3},
 					"arg4": BsonM{"bsonType": []string{"long"}, "minimum": 2.1},
 					"arg5": BsonM{"bsonType": []string{"double"}, "multipleOf": 2.3},
-					"arg6": BsonM{"bsonType": []string{"decimal"}},
+					"arg6": BsonM{"bsonType": []string{"double"}},
 					"arg7": BsonM{"bsonType": []string{"bool"}},
 					"arg8": BsonM{"bsonType": []string{"array"},
 						"items": BsonM{
 							"bsonType":  []string{"string"},
-							"enum":      []string{"a", "b", "c", "d"},
+							"enum":      []interface{}{"a", "b", "c", "d"},
 							"maxLength": 7, "minLength": 3},
 						"uniqueItems": true}},
 				"required": []string{"arg3", "arg4", "arg6", "arg7", "arg8"}},
@@ -152,31 +140,35 @@ func TestCreateJSONSchema(t *testing.T) {
 		"obj1": BsonM{
 			"bsonType": []string{"object"},
 			"properties": BsonM{
-				"arg2": BsonM{"bsonType": []string{"string"}, "pattern": "@gmail.com$", "patternProperties": "gi"},
+				"arg2": BsonM{"bsonType": []string{"string"}, "pattern": "@gmail.com$"},
 				"arg3": BsonM{"bsonType": []string{"int", "long"}, "multipleOf": 2.3},
 				"arg4": BsonM{"bsonType": []string{"long"}, "minimum": 2.1},
 				"arg5": BsonM{"bsonType": []string{"double"}, "multipleOf": 2.3},
-				"arg6": BsonM{"bsonType": []string{"decimal"}},
+				"arg6": BsonM{"bsonType": []string{"double"}},
 				"arg7": BsonM{"bsonType": []string{"bool"}},
 				"arg8": BsonM{
 					"bsonType": []string{"array"},
 					"items": BsonM{
 						"bsonType":  []string{"string"},
-						"enum":      []string{"a", "b", "c", "d"},
+						"enum":      []interface{}{"a", "b", "c", "d"},
 						"maxLength": 7, "minLength": 3},
 					"uniqueItems": true}},
 			"required": []string{"arg3", "arg4", "arg6", "arg7", "arg8"}}}
 	wantReq := []string{"_id", "arg3", "arg4", "arg6", "arg7", "arg8", "date", "arr", "obj1", "m"}
+	// patternProperties is only used by maps, the other fields are kept without it
+	wantErrs := []string{"arg2", "arg4", "arr.arg2", "arr.arg4", "obj1.arg2", "obj1.arg4"}
 
-	have := BsonM{}
-	required, errs := CreateJSONSchema(reflect.ValueOf(obj), &have)
+	have, required, errs := createTestSchema(reflect.TypeOf(createConfigTest{}), Options{})
 
 	if !reflect.DeepEqual(want, have) {
 		t.Errorf("Field: Object;\nGot: %#v;\nWant: %#v;", have, want)
 	}
-	if !tags.CompareArr(required, wantReq) || len(errs) > 0 {
+	if !tags.CompareArr(required, wantReq) || !tags.CompareArr(errorPaths(errs), wantErrs) {
 		t.Errorf("Field: Required;\nGot: %#v;\nWant: %#v;\nErrs: %#v", required, wantReq, errs)
 	}
+	if want := "[arg2]: the patternProperties validation can only be used with maps"; len(errs) == 0 || errs[0].Error() != want {
+		t.Errorf("Field: Errors;\nGot: %v;\nWant: %v", errs, want)
+	}
 }
 
 type createJSONSchemaTestErrs struct {
@@ -184,27 +176,21 @@ type createJSONSchemaTestErrs struct {
 	InvalidType      string   `type:"invalid"`
 	InvalidItems     []string `items:"invalid"`
 	InvalidItemsType []string `itemsType:"invalid"`
-	InvalidEmpty     []string
-	InvalidLen0      []string
+	InvalidChan      chan int
+	InvalidItemsChan []chan int
 }
 
 func TestCreateJSONSchemaErrs(t *testing.T) {
-	obj := createJSONSchemaTestErrs{
-		InvalidItems:     []string{""},
-		InvalidItemsType: []string{""},
-		InvalidLen0:      []string{},
-	}
 	want := BsonM{}
 
-	have := BsonM{}
-	reqs, errs := CreateJSONSchema(reflect.ValueOf(obj), &have)
+	have, reqs, errs := createTestSchema(reflect.TypeOf(createJSONSchemaTestErrs{}), Options{})
 	mustHaves := []string{
 		"invalid",
 		"invalidType",
 		"invalidItems",
 		"invalidItemsType",
-		"invalidEmpty",
-		"invalidLen0",
+		"invalidChan",
+		"invalidItemsChan",
 	}
 
 	errsM := map[string]bool{}
@@ -222,3 +208,549 @@ func TestCreateJSONSchemaErrs(t *testing.T) {
 		t.Errorf("Reqs: %#v;\nGot: %#v;\nWant: %#v;", reqs, have, want)
 	}
 }
+
+type createJSONSchemaTestTypes struct {
+	Date     time.Time
+	DateStr  time.Time `type:"string"`
+	Dates    []*time.Time
+	Bytes    []byte
+	Ptr      *createConfigTestItem `validation:"required"`
+	PtrArr   []*createConfigTestItem
+	PtrItems *[]*string
+}
+
+func TestCreateJSONSchemaTypes(t *testing.T) {
+	item := BsonM{
+		"bsonType": []string{"object"},
+		"properties": BsonM{
+			"arg2": BsonM{"bsonType": []string{"string"}, "pattern": "@gmail.com$"},
+			"arg3": BsonM{"bsonType": []string{"int", "long"}, "multipleOf": 2.3},
+			"arg4": BsonM{"bsonType": []string{"long"}, "minimum": 2.1},
+			"arg5": BsonM{"bsonType": []string{"double"}, "multipleOf": 2.3},
+			"arg6": BsonM{"bsonType": []string{"double"}},
+			"arg7": BsonM{"bsonType": []string{"bool"}},
+			"arg8": BsonM{"bsonType": []string{"array"},
+				"items": BsonM{
+					"bsonType":  []string{"string"},
+					"enum":      []interface{}{"a", "b", "c", "d"},
+					"maxLength": 7, "minLength": 3},
+				"uniqueItems": true}},
+		"required": []string{"arg3", "arg4", "arg6", "arg7", "arg8"}}
+	want := BsonM{
+		"date":    BsonM{"bsonType": []string{"date"}},
+		"dateStr": BsonM{"bsonType": []string{"string"}},
+		"dates":   BsonM{"bsonType": []string{"array"}, "items": BsonM{"bsonType": []string{"date"}}, "uniqueItems": false},
+		"bytes":   BsonM{"bsonType": []string{"binData"}},
+		"ptr":     item,
+		"ptrArr":  BsonM{"bsonType": []string{"array"}, "items": item, "uniqueItems": false},
+		"ptrItems": BsonM{
+			"bsonType":    []string{"array"},
+			"items":       BsonM{"bsonType": []string{"string"}},
+			"uniqueItems": false},
+	}
+	wantReq := []string{"ptr"}
+	wantErrs := []string{"ptr.arg2", "ptr.arg4", "ptrArr.arg2", "ptrArr.arg4"}
+
+	have, required, errs := createTestSchema(reflect.TypeOf(createJSONSchemaTestTypes{}), Options{})
+
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("Field: Object;\nGot: %#v;\nWant: %#v;", have, want)
+	}
+	if !tags.CompareArr(required, wantReq) || !tags.CompareArr(errorPaths(errs), wantErrs) {
+		t.Errorf("Field: Required;\nGot: %#v;\nWant: %#v;\nErrs: %#v", required, wantReq, errs)
+	}
+}
+
+type createJSONSchemaTestNumbers struct {
+	Int     int
+	Int16   int16
+	Uint8   uint8 `validation:"max=100"`
+	Uint    uint  `type:"int"`
+	Float   float64
+	Uint16s []uint16
+}
+
+type createJSONSchemaNumbersTest struct {
+	arg  Options
+	want BsonM
+}
+
+func TestCreateJSONSchemaNumbers(t *testing.T) {
+	tests := []createJSONSchemaNumbersTest{
+		{Options{NumericPolicy: tags.NumericStrict}, BsonM{
+			"int":   BsonM{"bsonType": []string{"int", "long"}},
+			"int16": BsonM{"bsonType": []string{"int"}, "minimum": float64(-32768), "maximum": float64(32767)},
+			"uint8": BsonM{"bsonType": []string{"int"}, "minimum": float64(0), "maximum": float64(100)},
+			"uint":  BsonM{"bsonType": []string{"int"}},
+			"float": BsonM{"bsonType": []string{"double"}},
+			"uint16s": BsonM{
+				"bsonType":    []string{"array"},
+				"items":       BsonM{"bsonType": []string{"int"}, "minimum": float64(0), "maximum": float64(65535)},
+				"uniqueItems": false},
+		}},
+		{Options{NumericPolicy: tags.NumericPermissive}, BsonM{
+			"int":   BsonM{"bsonType": []string{"int", "long"}},
+			"int16": BsonM{"bsonType": []string{"int", "long"}, "minimum": float64(-32768), "maximum": float64(32767)},
+			"uint8": BsonM{"bsonType": []string{"int", "long"}, "minimum": float64(0), "maximum": float64(100)},
+			"uint":  BsonM{"bsonType": []string{"int"}},
+			"float": BsonM{"bsonType": []string{"double", "int", "long"}},
+			"uint16s": BsonM{
+				"bsonType":    []string{"array"},
+				"items":       BsonM{"bsonType": []string{"int", "long"}, "minimum": float64(0), "maximum": float64(65535)},
+				"uniqueItems": false},
+		}},
+	}
+
+	for _, test := range tests {
+		have, _, errs := createTestSchema(reflect.TypeOf(createJSONSchemaTestNumbers{}), test.arg)
+
+		if !reflect.DeepEqual(test.want, have) || len(errs) > 0 {
+			t.Errorf("Policy: %v;\nGot: %#v;\nWant: %#v;\nErrs: %#v", test.arg.NumericPolicy, have, test.want, errs)
+		}
+	}
+}
+
+type createJSONSchemaTestNullableNumbers struct {
+	Uint8   *uint8 `validation:"max=300"`
+	Int8    *int8
+	Uint8s  []*uint8
+	Invalid *uint8 `validation:"min=300"`
+}
+
+// The bounds of the numeric kinds are kept with the null type
+func TestCreateJSONSchemaNullableNumbers(t *testing.T) {
+	want := BsonM{
+		"uint8": BsonM{"bsonType": []string{"int", "null"}, "minimum": float64(0), "maximum": float64(255)},
+		"int8":  BsonM{"bsonType": []string{"int", "null"}, "minimum": float64(-128), "maximum": float64(127)},
+		"uint8s": BsonM{
+			"bsonType":    []string{"array", "null"},
+			"items":       BsonM{"bsonType": []string{"int", "null"}, "minimum": float64(0), "maximum": float64(255)},
+			"uniqueItems": false},
+	}
+	wantErrs := []string{"invalid"}
+
+	have, _, errs := createTestSchema(reflect.TypeOf(createJSONSchemaTestNullableNumbers{}), Options{Nullable: true})
+
+	if !reflect.DeepEqual(want, have) || !tags.CompareArr(errorPaths(errs), wantErrs) {
+		t.Errorf("\nGot: %#v;\nWant: %#v;\nErrs: %#v", have, want, errs)
+	}
+}
+
+type createJSONSchemaTestNullable struct {
+	Name     string
+	Nick     string `bson:"nick,omitempty"`
+	Email    *string
+	Tags     []string
+	Date     *time.Time `type:"date,null"`
+	Price    *testMoney
+	Item     *createConfigTestItem `field:"item,omitempty" validation:"required"`
+	Inline   *createConfigTestItem `field:",inline"`
+	Settings map[string]string
+	// Pointer items are nullable like pointer fields
+	Prices []*testMoney
+	Scores map[string]*int
+	Grid   [][]*string
+	// Interfaces are nullable when they have bson types, without them any value is allowed
+	Ref  interface{}   `type:"objectId"`
+	Refs []interface{} `itemsType:"objectId"`
+	Any  interface{}
+}
+
+func TestCreateJSONSchemaNullable(t *testing.T) {
+	item := BsonM{
+		"arg2": BsonM{"bsonType": []string{"string"}, "pattern": "@gmail.com$"},
+		"arg3": BsonM{"bsonType": []string{"int", "long"}, "multipleOf": 2.3},
+		"arg4": BsonM{"bsonType": []string{"long"}, "minimum": 2.1},
+		"arg5": BsonM{"bsonType": []string{"double"}, "multipleOf": 2.3},
+		"arg6": BsonM{"bsonType": []string{"double"}},
+		"arg7": BsonM{"bsonType": []string{"bool"}},
+		"arg8": BsonM{"bsonType": []string{"array", "null"},
+			"items": BsonM{
+				"bsonType":  []string{"string", "null"},
+				"enum":      []interface{}{"a", "b", "c", "d", nil},
+				"maxLength": 7, "minLength": 3},
+			"uniqueItems": true}}
+	want := BsonM{
+		"name":  BsonM{"bsonType": []string{"string"}},
+		"nick":  BsonM{"bsonType": []string{"string", "null"}},
+		"email": BsonM{"bsonType": []string{"string", "null"}},
+		"tags": BsonM{
+			"bsonType":    []string{"array", "null"},
+			"items":       BsonM{"bsonType": []string{"string"}},
+			"uniqueItems": false},
+		"date":  BsonM{"bsonType": []string{"date", "null"}},
+		"price": BsonM{"bsonType": []string{"object", "null"}, "required": []string{"amount", "currency"}},
+		"item": BsonM{
+			"bsonType":   []string{"object", "null"},
+			"properties": item,
+			"required":   []string{"arg3", "arg4", "arg6", "arg7", "arg8"}},
+		"settings": BsonM{"bsonType": []string{"object", "null"}, "additionalProperties": BsonM{"bsonType": []string{"string"}}},
+		"prices": BsonM{
+			"bsonType":    []string{"array", "null"},
+			"items":       BsonM{"bsonType": []string{"object", "null"}, "required": []string{"amount", "currency"}},
+			"uniqueItems": false},
+		"scores": BsonM{"bsonType": []string{"object", "null"}, "additionalProperties": BsonM{"bsonType": []string{"int", "long", "null"}}},
+		"grid": BsonM{
+			"bsonType": []string{"array", "null"},
+			"items": BsonM{
+				"bsonType":    []string{"array"},
+				"items":       BsonM{"bsonType": []string{"string", "null"}},
+				"uniqueItems": false},
+			"uniqueItems": false},
+		"ref": BsonM{"bsonType": []string{"objectId", "null"}},
+		"refs": BsonM{
+			"bsonType":    []string{"array", "null"},
+			"items":       BsonM{"bsonType": []string{"objectId", "null"}},
+			"uniqueItems": false},
+		"any": BsonM{},
+	}
+	for k, v := range item {
+		want[k] = v
+	}
+	wantReq := []string{"item", "arg3", "arg4", "arg6", "arg7", "arg8"}
+	wantErrs := []string{"item.arg2", "item.arg4", "arg2", "arg4"}
+
+	have, required, errs := createTestSchema(reflect.TypeOf(createJSONSchemaTestNullable{}), Options{Nullable: true})
+
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("Field: Object;\nGot: %#v;\nWant: %#v;", have, want)
+	}
+	if !tags.CompareArr(required, wantReq) || !tags.CompareArr(errorPaths(errs), wantErrs) {
+		t.Errorf("Field: Required;\nGot: %#v;\nWant: %#v;\nErrs: %#v", required, wantReq, errs)
+	}
+}
+
+type createJSONSchemaTestMaps struct {
+	Labels   map[string]string `validation:"max=20" items:"max=64" enum:"a,b" description:"Labels of the item"`
+	Counts   map[string]uint8
+	Items    map[string]*createConfigTestItem `validation:"patternProperties=^[a-z]+$"`
+	Lists    map[string][]int
+	Nested   map[string]map[string]bool
+	Any      map[string]interface{} `validation:"patternProperties=^[a-z]{2}$"`
+	Ids      map[string]interface{} `itemsType:"objectId"`
+	Prices   map[string]testMoney   `items:"min=2"`
+	Invalid  map[string]chan int
+	Invalid2 map[string][]chan int
+}
+
+func TestCreateJSONSchemaMaps(t *testing.T) {
+	item := BsonM{
+		"bsonType": []string{"object"},
+		"properties": BsonM{
+			"arg2": BsonM{"bsonType": []string{"string"}, "pattern": "@gmail.com$"},
+			"arg3": BsonM{"bsonType": []string{"int", "long"}, "multipleOf": 2.3},
+			"arg4": BsonM{"bsonType": []string{"long"}, "minimum": 2.1},
+			"arg5": BsonM{"bsonType": []string{"double"}, "multipleOf": 2.3},
+			"arg6": BsonM{"bsonType": []string{"double"}},
+			"arg7": BsonM{"bsonType": []string{"bool"}},
+			"arg8": BsonM{"bsonType": []string{"array"},
+				"items": BsonM{
+					"bsonType":  []string{"string"},
+					"enum":      []interface{}{"a", "b", "c", "d"},
+					"maxLength": 7, "minLength": 3},
+				"uniqueItems": true}},
+		"required": []string{"arg3", "arg4", "arg6", "arg7", "arg8"}}
+	want := BsonM{
+		"labels": BsonM{
+			"bsonType":             []string{"object"},
+			"maxProperties":        20,
+			"description":          "Labels of the item",
+			"additionalProperties": BsonM{"bsonType": []string{"string"}, "maxLength": 64, "enum": []interface{}{"a", "b"}}},
+		"counts": BsonM{
+			"bsonType":             []string{"object"},
+			"additionalProperties": BsonM{"bsonType": []string{"int"}, "minimum": float64(0), "maximum": float64(255)}},
+		"items": BsonM{
+			"bsonType":             []string{"object"},
+			"patternProperties":    BsonM{"^[a-z]+$": item},
+			"additionalProperties": false},
+		"lists": BsonM{
+			"bsonType": []string{"object"},
+			"additionalProperties": BsonM{
+				"bsonType":    []string{"array"},
+				"items":       BsonM{"bsonType": []string{"int", "long"}},
+				"uniqueItems": false}},
+		"nested": BsonM{
+			"bsonType": []string{"object"},
+			"additionalProperties": BsonM{
+				"bsonType":             []string{"object"},
+				"additionalProperties": BsonM{"bsonType": []string{"bool"}}}},
+		"any": BsonM{
+			"bsonType":             []string{"object"},
+			"patternProperties":    BsonM{"^[a-z]{2}$": BsonM{}},
+			"additionalProperties": false},
+		"ids": BsonM{
+			"bsonType":             []string{"object"},
+			"additionalProperties": BsonM{"bsonType": []string{"objectId"}}},
+		"prices": BsonM{
+			"bsonType": []string{"object"},
+			"additionalProperties": BsonM{
+				"bsonType":      []string{"object"},
+				"required":      []string{"amount", "currency"},
+				"minProperties": 2}},
+		"invalid2": BsonM{
+			"bsonType":             []string{"object"},
+			"additionalProperties": BsonM{"bsonType": []string{"array"}, "uniqueItems": false}},
+	}
+	wantErrs := []string{"items.arg2", "items.arg4", "invalid", "invalid2"}
+
+	have, _, errs := createTestSchema(reflect.TypeOf(createJSONSchemaTestMaps{}), Options{})
+
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("Field: Object;\nGot: %#v;\nWant: %#v;", have, want)
+	}
+	if haveErrs := errorPaths(errs); !tags.CompareArr(haveErrs, wantErrs) {
+		t.Errorf("Field: Errors;\nGot: %#v;\nWant: %#v;\nErrs: %#v", haveErrs, wantErrs, errs)
+	}
+}
+
+// Creates the json schema of typ and returns its properties and required fields
+// Gets the paths of the warnings of the walk
+func errorPaths(errs []error) []string {
+	out := []string{}
+	for _, err := range errs {
+		out = append(out, err.(ErrorWithTag).Path())
+	}
+	return out
+}
+
+func createTestSchema(typ reflect.Type, opts Options) (BsonM, []string, []error) {
+	schema := &jsonschema.Schema{}
+	errs := CreateJSONSchema(typ, opts, schema)
+	obj := schema.ToBsonM()
+	return obj["properties"].(BsonM), obj["required"].([]string), errs
+}
+
+// The schema of the walk is written the same way after it is parsed
+func TestCreateJSONSchemaRoundTrip(t *testing.T) {
+	tests := []reflect.Type{
+		reflect.TypeOf(nestedTest{}),
+		reflect.TypeOf(objectTestStrict{}),
+		reflect.TypeOf(createConfigTestItem{}),
+	}
+
+	for _, typ := range tests {
+		schema := &jsonschema.Schema{BsonType: []string{"object"}, Title: jsonschema.CreateVal("Round Trip")}
+		CreateJSONSchema(typ, Options{InferRequired: true}, schema)
+		obj := schema.ToBsonM()
+
+		parsed, err := jsonschema.Parse(obj)
+		if err != nil {
+			t.Errorf("Type: %v;\nError: %v", typ, err)
+			continue
+		}
+		if have := parsed.ToBsonM(); !reflect.DeepEqual(have, obj) {
+			t.Errorf("Type: %v;\nGot: %#v;\nWant: %#v", typ, have, obj)
+		}
+	}
+}
+
+type createJSONSchemaTestBsonItem struct {
+	Street string `bson:"street"`
+	Zip    string `bson:"zip,omitempty"`
+}
+
+type createJSONSchemaTestBson struct {
+	ID       interface{}                  `bson:"_id,omitempty"`
+	Name     string                       `bson:"name"`
+	Skipped  string                       `bson:"-"`
+	Skipped2 string                       `field:"-" bson:"skipped2"`
+	Dash     string                       `bson:"-,"`
+	Count    int64                        `bson:"count,minsize"`
+	Counts   []uint32                     `bson:"counts,minsize"`
+	Ratio    float64                      `bson:"ratio,truncate"`
+	Address  createJSONSchemaTestBsonItem `bson:",inline"`
+	Extra    map[string]string            `bson:",inline"`
+}
+
+type createJSONSchemaTestBsonMap struct {
+	Name  string                 `bson:"name"`
+	Extra map[string]interface{} `bson:"extra,inline" validation:"patternProperties=^x-"`
+}
+
+type CreateJSONSchemaTestBsonAudit struct {
+	CreatedBy string `bson:"createdBy"`
+}
+
+type CreateJSONSchemaTestBsonOwner struct {
+	Email string `bson:"email"`
+}
+
+// Embedded structs are flattened unless their tags have a name
+type createJSONSchemaTestBsonEmbedded struct {
+	CreateJSONSchemaTestBsonAudit
+	createJSONSchemaTestBsonItem
+	CreateJSONSchemaTestBsonOwner `bson:"owner"`
+	Name                          string `bson:"name"`
+}
+
+type createJSONSchemaBsonTest struct {
+	arg1 reflect.Type
+	arg2 Options
+	want BsonM
+}
+
+func TestCreateJSONSchemaBson(t *testing.T) {
+	tests := []createJSONSchemaBsonTest{
+		{reflect.TypeOf(createJSONSchemaTestBson{}), Options{}, BsonM{
+			"properties": BsonM{
+				"_id":   BsonM{},
+				"name":  BsonM{"bsonType": []string{"string"}},
+				"-":     BsonM{"bsonType": []string{"string"}},
+				"count": BsonM{"bsonType": []string{"int", "long"}},
+				"counts": BsonM{
+					"bsonType":    []string{"array"},
+					"items":       BsonM{"bsonType": []string{"int", "long"}, "minimum": float64(0), "maximum": float64(4294967295)},
+					"uniqueItems": false},
+				"ratio":  BsonM{"bsonType": []string{"double"}},
+				"street": BsonM{"bsonType": []string{"string"}},
+				"zip":    BsonM{"bsonType": []string{"string"}}},
+			"required":             []string{},
+			"additionalProperties": BsonM{"bsonType": []string{"string"}}}},
+		{reflect.TypeOf(createJSONSchemaTestBson{}), Options{InferRequired: true}, BsonM{
+			"properties": BsonM{
+				"_id":   BsonM{},
+				"name":  BsonM{"bsonType": []string{"string"}},
+				"-":     BsonM{"bsonType": []string{"string"}},
+				"count": BsonM{"bsonType": []string{"int", "long"}},
+				"counts": BsonM{
+					"bsonType":    []string{"array"},
+					"items":       BsonM{"bsonType": []string{"int", "long"}, "minimum": float64(0), "maximum": float64(4294967295)},
+					"uniqueItems": false},
+				"ratio":  BsonM{"bsonType": []string{"double"}},
+				"street": BsonM{"bsonType": []string{"string"}},
+				"zip":    BsonM{"bsonType": []string{"string"}}},
+			"required":             []string{"name", "-", "count", "counts", "ratio", "street"},
+			"additionalProperties": BsonM{"bsonType": []string{"string"}}}},
+		{reflect.TypeOf(createJSONSchemaTestBsonMap{}), Options{}, BsonM{
+			"properties": BsonM{
+				"_id":  BsonM{"bsonType": []string{"objectId"}},
+				"name": BsonM{"bsonType": []string{"string"}}},
+			"required":             []string{},
+			"patternProperties":    BsonM{"^x-": BsonM{}},
+			"additionalProperties": false}},
+		{reflect.TypeOf(createJSONSchemaTestBsonEmbedded{}), Options{NamingPolicy: tags.NamingLowerCase}, BsonM{
+			"properties": BsonM{
+				"createdBy": BsonM{"bsonType": []string{"string"}},
+				"street":    BsonM{"bsonType": []string{"string"}},
+				"zip":       BsonM{"bsonType": []string{"string"}},
+				"owner": BsonM{
+					"bsonType":   []string{"object"},
+					"properties": BsonM{"email": BsonM{"bsonType": []string{"string"}}},
+					"required":   []string{}},
+				"name": BsonM{"bsonType": []string{"string"}}},
+			"required": []string{}}},
+	}
+
+	for _, test := range tests {
+		haveSchema := &jsonschema.Schema{}
+		errs := CreateJSONSchema(test.arg1, test.arg2, haveSchema)
+		have := haveSchema.ToBsonM()
+
+		if !reflect.DeepEqual(have, test.want) || len(errs) > 0 {
+			t.Errorf("Type: %v;\nGot: %#v;\nWant: %#v;\nErrs: %#v", test.arg1, have, test.want, errs)
+		}
+	}
+}
+
+type createJSONSchemaTestEnum struct {
+	Priority int               `enum:"1, 2, 3"`
+	Level    int8              `enum:"-1,0,1"`
+	Big      int64             `enum:"1,5000000000"`
+	Ratio    float64           `enum:"0.5,1"`
+	Active   bool              `enum:"true"`
+	Status   string            `enum:"'a, b',c,'it\\'s'"`
+	Mixed    interface{}       `type:"string,int,null" enum:"1,'1',one,null"`
+	Nick     *string           `enum:"a,null"`
+	Code     *string           `enum:"a,b"`
+	Kind     string            `type:"string,null" enum:"a"`
+	Scores   []int32           `enum:"1,2"`
+	Flags    map[string]bool   `enum:"false"`
+	Invalid  int               `enum:"a,1"`
+	Invalid2 string            `enum:"'a"`
+	Invalid3 []float64         `enum:"'1'"`
+	Invalid4 map[string]uint16 `enum:"true"`
+}
+
+func TestCreateJSONSchemaEnum(t *testing.T) {
+	want := BsonM{
+		"priority": BsonM{"bsonType": []string{"int", "long"}, "enum": []interface{}{int32(1), int32(2), int32(3)}},
+		"level":    BsonM{"bsonType": []string{"int"}, "minimum": float64(-128), "maximum": float64(127), "enum": []interface{}{int32(-1), int32(0), int32(1)}},
+		"big":      BsonM{"bsonType": []string{"long"}, "enum": []interface{}{int64(1), int64(5000000000)}},
+		"ratio":    BsonM{"bsonType": []string{"double"}, "enum": []interface{}{0.5, float64(1)}},
+		"active":   BsonM{"bsonType": []string{"bool"}, "enum": []interface{}{true}},
+		"status":   BsonM{"bsonType": []string{"string"}, "enum": []interface{}{"a, b", "c", "it's"}},
+		"mixed":    BsonM{"bsonType": []string{"string", "int", "null"}, "enum": []interface{}{int32(1), "1", "one", nil}},
+		"nick":     BsonM{"bsonType": []string{"string", "null"}, "enum": []interface{}{"a", nil}},
+		"code":     BsonM{"bsonType": []string{"string", "null"}, "enum": []interface{}{"a", "b", nil}},
+		"kind":     BsonM{"bsonType": []string{"string", "null"}, "enum": []interface{}{"a", nil}},
+		"scores": BsonM{
+			"bsonType":    []string{"array", "null"},
+			"items":       BsonM{"bsonType": []string{"int"}, "minimum": float64(-2147483648), "maximum": float64(2147483647), "enum": []interface{}{int32(1), int32(2)}},
+			"uniqueItems": false},
+		"flags": BsonM{
+			"bsonType":             []string{"object", "null"},
+			"additionalProperties": BsonM{"bsonType": []string{"bool"}, "enum": []interface{}{false}}},
+	}
+	wantErrs := []string{"invalid", "invalid2", "invalid3", "invalid4"}
+
+	have, _, errs := createTestSchema(reflect.TypeOf(createJSONSchemaTestEnum{}), Options{Nullable: true})
+
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("Field: Object;\nGot: %#v;\nWant: %#v;", have, want)
+	}
+	haveErrs := []string{}
+	for _, err := range errs {
+		haveErrs = append(haveErrs, err.(ErrorWithTag).Tag())
+	}
+	if !tags.CompareArr(haveErrs, wantErrs) {
+		t.Errorf("Field: Errors;\nGot: %#v;\nWant: %#v;\nErrs: %#v", haveErrs, wantErrs, errs)
+	}
+}
+
+type createJSONSchemaTestTags struct {
+	Code     string   `validation:"pattern=^[A-Z]{2,3}-[0-9]{1,5}$,max=9"`
+	Email    string   `validation:"pattern='^[^@,]+@[a-z]+\\.com$'"`
+	Query    string   `validation:"pattern=^a=b$"`
+	Tags     []string `items:"pattern=^#[a-z]{1,20}$" enum:"'#a, b',#c"`
+	Kinds    string   `type:"string, 'null'"`
+	Invalid  string   `validation:"min=1,pattern=^[a-z"`
+	Invalid2 []string `items:"pattern='^a"`
+	Invalid3 string   `enum:"a,'b' c"`
+	Invalid4 string   `type:"string,'null"`
+	Invalid5 string   `validation:"min=1, max=a"`
+	Invalid6 []int    `itemsType:"int,'long"`
+}
+
+func TestCreateJSONSchemaTags(t *testing.T) {
+	want := BsonM{
+		"code":  BsonM{"bsonType": []string{"string"}, "pattern": "^[A-Z]{2,3}-[0-9]{1,5}$", "maxLength": 9},
+		"email": BsonM{"bsonType": []string{"string"}, "pattern": `^[^@,]+@[a-z]+\.com$`},
+		"query": BsonM{"bsonType": []string{"string"}, "pattern": "^a=b$"},
+		"tags": BsonM{
+			"bsonType":    []string{"array"},
+			"items":       BsonM{"bsonType": []string{"string"}, "pattern": "^#[a-z]{1,20}$", "enum": []interface{}{"#a, b", "#c"}},
+			"uniqueItems": false},
+		"kinds": BsonM{"bsonType": []string{"string", "null"}},
+	}
+	wantErrs := []string{
+		"[invalid]: invalid [validation] tag at offset 15: unclosed [[], quote the value if it is intended",
+		"[invalid2]: invalid [items] tag at offset 8: unterminated quote",
+		"[invalid3]: invalid [enum] tag at offset 6: unexpected character [c] after a quoted value, expected a comma",
+		"[invalid4]: invalid [type] tag at offset 7: unterminated quote",
+		"[invalid5]: invalid [validation] tag at offset 7: invalid value of [max], strconv.ParseFloat: parsing \"a\": invalid syntax",
+		"[invalid6]: invalid [itemsType] tag at offset 4: unterminated quote",
+	}
+
+	have, _, errs := createTestSchema(reflect.TypeOf(createJSONSchemaTestTags{}), Options{})
+
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("Field: Object;\nGot: %#v;\nWant: %#v;", have, want)
+	}
+	haveErrs := []string{}
+	for _, err := range errs {
+		haveErrs = append(haveErrs, err.Error())
+	}
+	if !tags.CompareArr(haveErrs, wantErrs) {
+		t.Errorf("Field: Errors;\nGot: %#v;\nWant: %#v", haveErrs, wantErrs)
+	}
+}
@@ -0,0 +1,70 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/s-augustovitko/mongo-schema-go/internal/tags"
+	"github.com/s-augustovitko/mongo-schema-go/pkg/schema/jsonschema"
+)
+
+const tagTuple = "tuple"
+
+// Parses the tuple tag, the schema of each position of an array
+// each value is a bson type, the name of a registered fragment or a json object
+// quoted values are always fragment names, so fragments can be named like bson types
+func parseTuple(field reflect.StructField) ([]fragment, error) {
+	tuple := field.Tag.Get(tagTuple)
+	if tuple == "" {
+		return nil, nil
+	}
+
+	items, err := tags.SplitNested(tuple)
+	if err != nil {
+		return nil, tags.WithTagName(tagTuple, err)
+	}
+
+	out := make([]fragment, 0, len(items))
+	for _, item := range items {
+		if types, err := tags.ParseTypes(item.Val); !item.Quoted && err == nil && len(types) == 1 {
+			out = append(out, fragment{Schema: &jsonschema.Schema{BsonType: types}})
+			continue
+		}
+
+		frag, err := parseFragment(item)
+		if err != nil {
+			return nil, &tags.SyntaxError{Tag: tagTuple, Offset: item.Offset, Msg: fmt.Sprintf("%v, it is not a bson type either", err)}
+		}
+		out = append(out, frag)
+	}
+	return out, nil
+}
+
+// Checks that the tuple can be used with the field
+func checkTuple(cfg config, typ reflect.Type, field reflect.StructField) error {
+	if !cfg.IsArray {
+		return fmt.Errorf("the tuple tag can only be used with arrays")
+	}
+	if typ.Kind() == reflect.Array && typ.Len() != len(cfg.Tuple) {
+		return fmt.Errorf("the tuple has %v items but the array has a length of %v", len(cfg.Tuple), typ.Len())
+	}
+	if field.Tag.Get(tagItemsType) != "" || field.Tag.Get(tagItems) != "" || field.Tag.Get(tagEnum) != "" {
+		return fmt.Errorf("the tuple tag can not be used with the itemsType, items or enum tags")
+	}
+	return nil
+}
+
+// Creates the items of a tuple, items that are not in the tuple are not allowed
+func addTuple(tuple []fragment, opts Options, w walk, obj *jsonschema.Schema) []error {
+	errors := []error{}
+	items := make([]*jsonschema.Schema, 0, len(tuple))
+	for _, frag := range tuple {
+		schema, errs := fragmentSchema(frag, opts, w)
+		errors = append(errors, errs...)
+		items = append(items, schema)
+	}
+
+	obj.TupleItems = items
+	obj.AdditionalItems = &jsonschema.BoolOrSchema{Allow: false}
+	return errors
+}
@@ -0,0 +1,67 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/s-augustovitko/mongo-schema-go/internal/tags"
+	"github.com/s-augustovitko/mongo-schema-go/pkg/schema/jsonschema"
+)
+
+type tupleTest struct {
+	Coords   [2]float64    `bson:"coords"`
+	Color    [3]uint8      `bson:"color"`
+	Bounded  [3]string     `bson:"bounded" validation:"min=1"`
+	Nested   [][2]int      `bson:"nested"`
+	Position [2]float64    `bson:"position" tuple:"double,{\"bsonType\": \"double\", \"minimum\": -90, \"maximum\": 90}" description:"Longitude and latitude"`
+	Pair     []interface{} `bson:"pair" tuple:"string,int" validation:"min=1"`
+	Invalid  string        `bson:"invalid" tuple:"string"`
+	Invalid2 [2]int        `bson:"invalid2" tuple:"int"`
+	Invalid3 []int         `bson:"invalid3" tuple:"int" itemsType:"long"`
+	Invalid4 []int         `bson:"invalid4" tuple:"int,unknown"`
+}
+
+func TestCreateJSONSchemaTuple(t *testing.T) {
+	want := BsonM{
+		"coords": BsonM{"bsonType": []string{"array"}, "items": BsonM{"bsonType": []string{"double"}}, "uniqueItems": false,
+			"minItems": 2, "maxItems": 2},
+		"color": BsonM{"bsonType": []string{"array"}, "items": BsonM{"bsonType": []string{"int"}, "minimum": float64(0), "maximum": float64(255)},
+			"uniqueItems": false, "minItems": 3, "maxItems": 3},
+		"bounded": BsonM{"bsonType": []string{"array"}, "items": BsonM{"bsonType": []string{"string"}}, "uniqueItems": false,
+			"minItems": 1, "maxItems": 3},
+		"nested": BsonM{"bsonType": []string{"array"}, "uniqueItems": false, "items": BsonM{"bsonType": []string{"array"},
+			"items": BsonM{"bsonType": []string{"int", "long"}}, "uniqueItems": false, "minItems": 2, "maxItems": 2}},
+		"position": BsonM{"bsonType": []string{"array"}, "description": "Longitude and latitude", "uniqueItems": false, "minItems": 2, "maxItems": 2,
+			"items": []BsonM{
+				{"bsonType": []string{"double"}},
+				{"bsonType": []string{"double"}, "minimum": -90.0, "maximum": 90.0},
+			},
+			"additionalItems": false},
+		"pair": BsonM{"bsonType": []string{"array"}, "uniqueItems": false, "minItems": 1,
+			"items":           []BsonM{{"bsonType": []string{"string"}}, {"bsonType": []string{"int"}}},
+			"additionalItems": false},
+	}
+	wantErrs := []string{
+		"[invalid]: the tuple tag can only be used with arrays",
+		"[invalid2]: the tuple has 1 items but the array has a length of 2",
+		"[invalid3]: the tuple tag can not be used with the itemsType, items or enum tags",
+		"[invalid4]: invalid [tuple] tag at offset 4: fragment [unknown] is not registered, it is not a bson type either",
+	}
+
+	haveSchema := &jsonschema.Schema{}
+	errs := CreateJSONSchema(reflect.TypeOf(tupleTest{}), Options{}, haveSchema)
+	have := haveSchema.ToBsonM()
+
+	for k, v := range want {
+		if !reflect.DeepEqual(have["properties"].(BsonM)[k], v) {
+			t.Errorf("Field: %v;\nGot: %#v;\nWant: %#v", k, have["properties"].(BsonM)[k], v)
+		}
+	}
+	haveErrs := []string{}
+	for _, err := range errs {
+		haveErrs = append(haveErrs, err.Error())
+	}
+	if !tags.CompareArr(haveErrs, wantErrs) {
+		t.Errorf("Field: Errors;\nGot: %#v;\nWant: %#v", haveErrs, wantErrs)
+	}
+}
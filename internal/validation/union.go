@@ -0,0 +1,115 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/s-augustovitko/mongo-schema-go/internal/tags"
+	"github.com/s-augustovitko/mongo-schema-go/pkg/schema/jsonschema"
+)
+
+// Variants of an interface type, the discriminator field of each variant has the value of its key
+type union struct {
+	Discriminator string
+	Values        []string
+	Variants      map[string]reflect.Type
+}
+
+// Unions registered by interface type
+var unions = struct {
+	sync.RWMutex
+	items map[reflect.Type]union
+}{
+	items: map[reflect.Type]union{},
+}
+
+// Registers the variants of an interface type, fields of the type are described as a oneOf of the variants
+// variants must be structs (or pointers to them) that implement the interface
+func RegisterUnion(iface reflect.Type, discriminator string, variants map[string]reflect.Type) error {
+	if iface == nil || iface.Kind() != reflect.Interface {
+		return fmt.Errorf("[%v]: unions can only be registered for interface types", iface)
+	}
+	if discriminator == "" {
+		return fmt.Errorf("[%v]: the discriminator field can not be empty", iface)
+	}
+	if len(variants) == 0 {
+		return fmt.Errorf("[%v]: at least one variant is required", iface)
+	}
+
+	item := union{Discriminator: discriminator, Variants: map[string]reflect.Type{}}
+	for value, typ := range variants {
+		if typ == nil {
+			return fmt.Errorf("[%v]: the variant [%v] can not be nil", iface, value)
+		}
+		if !typ.Implements(iface) && !reflect.PointerTo(typ).Implements(iface) {
+			return fmt.Errorf("[%v]: the variant [%v] of type [%v] does not implement the interface", iface, value, typ)
+		}
+		if indirectType(typ).Kind() != reflect.Struct {
+			return fmt.Errorf("[%v]: the variant [%v] of type [%v] is not a struct", iface, value, typ)
+		}
+
+		item.Values = append(item.Values, value)
+		item.Variants[value] = indirectType(typ)
+	}
+	sort.Strings(item.Values)
+
+	unions.Lock()
+	defer unions.Unlock()
+	unions.items[iface] = item
+	return nil
+}
+
+func lookupUnion(typ reflect.Type) (union, bool) {
+	unions.RLock()
+	defer unions.RUnlock()
+
+	item, ok := unions.items[typ]
+	return item, ok
+}
+
+// Gets the bson types of typ, interfaces with a union are objects
+func getType(typeTag string, typ reflect.Type, opts Options) ([]string, error) {
+	if _, ok := lookupUnion(typ); ok && typeTag == "" {
+		return []string{"object"}, nil
+	}
+	return tags.GetType(typeTag, typ, opts.NumericPolicy)
+}
+
+// Adds the variants of the union of typ as a oneOf to obj, each variant pins the discriminator with its value
+func addUnion(typ reflect.Type, opts Options, w walk, obj *jsonschema.Schema) []error {
+	item, ok := lookupUnion(typ)
+	if !ok {
+		return []error{}
+	}
+
+	errors := []error{}
+	variants := make([]*jsonschema.Schema, 0, len(item.Values))
+	for _, value := range item.Values {
+		schema, errs := typeSchema(item.Variants[value], opts, w)
+		errors = append(errors, errs...)
+
+		if schema.Properties == nil {
+			schema.Properties = map[string]*jsonschema.Schema{}
+		}
+		discriminator := schema.Properties[item.Discriminator]
+		if discriminator == nil {
+			discriminator = &jsonschema.Schema{BsonType: []string{"string"}}
+			schema.Properties[item.Discriminator] = discriminator
+		}
+		discriminator.Enum = []interface{}{value}
+
+		if !tags.Contains(schema.Required, item.Discriminator) {
+			schema.Required = append(schema.Required, item.Discriminator)
+		}
+		variants = append(variants, schema)
+	}
+	// The driver writes null for nil interfaces, so it is a variant when the types allow it (eg. with the Nullable option)
+	if tags.Contains(obj.BsonType, "null") {
+		variants = append(variants, &jsonschema.Schema{BsonType: []string{"null"}})
+	}
+
+	obj.OneOf = variants
+	return errors
+}
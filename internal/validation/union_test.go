@@ -0,0 +1,157 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+)
+
+type unionTestPayload interface {
+	payload()
+}
+
+type unionTestCreated struct {
+	Kind string `bson:"kind" validation:"required"`
+	Name string `bson:"name"`
+}
+
+func (unionTestCreated) payload() {}
+
+type unionTestDeleted struct {
+	Reason string `bson:"reason"`
+}
+
+func (*unionTestDeleted) payload() {}
+
+type unionTestOther struct{}
+
+type unionTestInt int
+
+func (unionTestInt) payload() {}
+
+type registerUnionTest struct {
+	arg1    reflect.Type
+	arg2    string
+	arg3    map[string]reflect.Type
+	haveErr bool
+}
+
+func TestRegisterUnion(t *testing.T) {
+	iface := reflect.TypeOf((*unionTestPayload)(nil)).Elem()
+	tests := []registerUnionTest{
+		{iface, "kind", map[string]reflect.Type{"created": reflect.TypeOf(unionTestCreated{}), "deleted": reflect.TypeOf(&unionTestDeleted{})}, false},
+		{iface, "kind", map[string]reflect.Type{"deleted": reflect.TypeOf(unionTestDeleted{})}, false},
+		{nil, "kind", map[string]reflect.Type{"created": reflect.TypeOf(unionTestCreated{})}, true},
+		{reflect.TypeOf(unionTestCreated{}), "kind", map[string]reflect.Type{"created": reflect.TypeOf(unionTestCreated{})}, true},
+		{iface, "", map[string]reflect.Type{"created": reflect.TypeOf(unionTestCreated{})}, true},
+		{iface, "kind", map[string]reflect.Type{}, true},
+		{iface, "kind", map[string]reflect.Type{"created": nil}, true},
+		{iface, "kind", map[string]reflect.Type{"other": reflect.TypeOf(unionTestOther{})}, true},
+		{iface, "kind", map[string]reflect.Type{"int": reflect.TypeOf(unionTestInt(0))}, true},
+	}
+
+	for _, test := range tests {
+		err := RegisterUnion(test.arg1, test.arg2, test.arg3)
+
+		if haveErr := err != nil; haveErr != test.haveErr {
+			t.Errorf("\nArg: %v, %v, %#v;\nErr: %#v", test.arg1, test.arg2, test.arg3, err)
+		}
+	}
+}
+
+type createJSONSchemaTestUnion struct {
+	Payload  unionTestPayload            `bson:"payload" description:"Event payload"`
+	Payloads []unionTestPayload          `bson:"payloads"`
+	ByID     map[string]unionTestPayload `bson:"byId"`
+	Typed    unionTestPayload            `bson:"typed" type:"string"`
+	Any      interface{}                 `bson:"any"`
+	// The union is added to allOf next to the combinators of the tags
+	Checked unionTestPayload `bson:"checked" oneOf:"{\"required\": [\"name\"]}, {\"required\": [\"reason\"]}"`
+	Limited unionTestPayload `bson:"limited" type:"object" allOf:"{\"maxProperties\": 3}"`
+}
+
+type createJSONSchemaTestNullableUnion struct {
+	Payload  unionTestPayload   `bson:"payload"`
+	Payloads []unionTestPayload `bson:"payloads"`
+	Checked  unionTestPayload   `bson:"checked" oneOf:"{\"required\": [\"name\"]}, {\"required\": [\"reason\"]}"`
+}
+
+func TestCreateJSONSchemaUnion(t *testing.T) {
+	iface := reflect.TypeOf((*unionTestPayload)(nil)).Elem()
+	err := RegisterUnion(iface, "kind", map[string]reflect.Type{
+		"deleted": reflect.TypeOf(unionTestDeleted{}),
+		"created": reflect.TypeOf(unionTestCreated{}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	union := func() BsonM {
+		return BsonM{
+			"bsonType": []string{"object"},
+			"oneOf": []BsonM{
+				{
+					"bsonType": []string{"object"},
+					"properties": BsonM{
+						"kind": BsonM{"bsonType": []string{"string"}, "enum": []interface{}{"created"}},
+						"name": BsonM{"bsonType": []string{"string"}}},
+					"required": []string{"kind"}},
+				{
+					"bsonType": []string{"object"},
+					"properties": BsonM{
+						"reason": BsonM{"bsonType": []string{"string"}},
+						"kind":   BsonM{"bsonType": []string{"string"}, "enum": []interface{}{"deleted"}}},
+					"required": []string{"kind"}},
+			},
+		}
+	}
+	payload := union()
+	payload["description"] = "Event payload"
+	want := BsonM{
+		"payload": payload,
+		"payloads": BsonM{
+			"bsonType":    []string{"array"},
+			"items":       union(),
+			"uniqueItems": false},
+		"byId": BsonM{
+			"bsonType":             []string{"object"},
+			"additionalProperties": union()},
+		"typed": BsonM{"bsonType": []string{"string"}},
+		"any":   BsonM{},
+		"checked": BsonM{
+			"oneOf": []BsonM{{"required": []string{"name"}}, {"required": []string{"reason"}}},
+			"allOf": []BsonM{union()}},
+		"limited": BsonM{
+			"bsonType": []string{"object"},
+			"allOf":    []BsonM{{"maxProperties": 3}, union()}},
+	}
+
+	have, _, errs := createTestSchema(reflect.TypeOf(createJSONSchemaTestUnion{}), Options{})
+
+	if !reflect.DeepEqual(want, have) || len(errs) > 0 {
+		t.Errorf("\nGot: %#v;\nWant: %#v;\nErrs: %#v", have, want, errs)
+	}
+
+	// Nil interfaces are written as null, so null is one of the variants with the Nullable option
+	nullable := func() BsonM {
+		out := union()
+		out["bsonType"] = []string{"object", "null"}
+		out["oneOf"] = append(out["oneOf"].([]BsonM), BsonM{"bsonType": []string{"null"}})
+		return out
+	}
+	want = BsonM{
+		"payload": nullable(),
+		"payloads": BsonM{
+			"bsonType":    []string{"array", "null"},
+			"items":       nullable(),
+			"uniqueItems": false},
+		"checked": BsonM{
+			"oneOf": []BsonM{{"required": []string{"name"}}, {"required": []string{"reason"}}},
+			"allOf": []BsonM{nullable()}},
+	}
+
+	have, _, errs = createTestSchema(reflect.TypeOf(createJSONSchemaTestNullableUnion{}), Options{Nullable: true})
+
+	if !reflect.DeepEqual(want, have) || len(errs) > 0 {
+		t.Errorf("\nGot: %#v;\nWant: %#v;\nErrs: %#v", have, want, errs)
+	}
+}
@@ -0,0 +1,47 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Position of the walk in the struct, used for the path of the warnings and to detect recursive types
+// Path is the dotted path of the current object, array items and map values do not add to it
+type walk struct {
+	Path    []string
+	Parents []reflect.Type
+}
+
+// Gets the walk of a field of the current object
+func (w walk) field(tag string) walk {
+	return walk{Path: append(append([]string{}, w.Path...), tag), Parents: w.Parents}
+}
+
+// Gets the walk inside a struct of type typ
+func (w walk) enter(typ reflect.Type) walk {
+	return walk{Path: w.Path, Parents: append(append([]reflect.Type{}, w.Parents...), typ)}
+}
+
+// Counts how many times typ is already being walked
+func (w walk) count(typ reflect.Type) int {
+	count := 0
+	for _, parent := range w.Parents {
+		if parent == typ {
+			count++
+		}
+	}
+	return count
+}
+
+// Checks if the struct a type resolves to (through pointers, arrays, slices and maps) is already being walked
+func (w walk) isRecursive(typ reflect.Type) bool {
+	typ = indirectType(typ)
+	for typ.Kind() == reflect.Array || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Map {
+		typ = indirectType(typ.Elem())
+	}
+	return typ.Kind() == reflect.Struct && w.count(typ) > 0
+}
+
+func (w walk) String() string {
+	return strings.Join(w.Path, ".")
+}
@@ -0,0 +1,120 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testWalkComment struct {
+	Text    string                       `validation:"required"`
+	Replies []*testWalkComment           `bson:"replies"`
+	Parent  *testWalkComment             `bson:"parent"`
+	ByUser  map[string][]testWalkComment `bson:"byUser"`
+}
+
+type testWalkUnit struct {
+	Name string
+	Org  *testWalkOrg `bson:"org"`
+}
+
+type testWalkOrg struct {
+	Name  string
+	Units []testWalkUnit `bson:"units"`
+}
+
+type isRecursiveTest struct {
+	arg  reflect.Type
+	want bool
+}
+
+func TestIsRecursive(t *testing.T) {
+	w := walk{}.enter(reflect.TypeOf(testWalkComment{}))
+	tests := []isRecursiveTest{
+		{reflect.TypeOf(testWalkComment{}), true},
+		{reflect.TypeOf(&testWalkComment{}), true},
+		{reflect.TypeOf([]*testWalkComment{}), true},
+		{reflect.TypeOf(map[string][][]testWalkComment{}), true},
+		{reflect.TypeOf(testWalkOrg{}), false},
+		{reflect.TypeOf([]string{}), false},
+	}
+
+	for _, test := range tests {
+		if have := w.isRecursive(test.arg); have != test.want {
+			t.Errorf("\nType: %v;\nGot: %#v;\nWant: %#v", test.arg, have, test.want)
+		}
+	}
+}
+
+func TestWalkPath(t *testing.T) {
+	w := walk{}.field("units").enter(reflect.TypeOf(testWalkUnit{})).field("org")
+	if have := w.String(); have != "units.org" {
+		t.Errorf("\nGot: %#v;\nWant: %#v", have, "units.org")
+	}
+	if have := w.count(reflect.TypeOf(testWalkUnit{})); have != 1 {
+		t.Errorf("\nGot: %#v;\nWant: %#v", have, 1)
+	}
+}
+
+type createJSONSchemaRecursiveTest struct {
+	arg       reflect.Type
+	arg2      int
+	want      BsonM
+	wantPaths []string
+}
+
+func TestCreateJSONSchemaRecursive(t *testing.T) {
+	comment := func(props BsonM) BsonM {
+		out := BsonM{"bsonType": []string{"object"}}
+		if props != nil {
+			out["properties"] = props
+			out["required"] = []string{"text"}
+		}
+		return out
+	}
+	commentProps := func(inner BsonM) BsonM {
+		return BsonM{
+			"text":    BsonM{"bsonType": []string{"string"}},
+			"replies": BsonM{"bsonType": []string{"array"}, "items": inner, "uniqueItems": false},
+			"parent":  inner,
+			"byUser": BsonM{
+				"bsonType": []string{"object"},
+				"additionalProperties": BsonM{
+					"bsonType":    []string{"array"},
+					"items":       inner,
+					"uniqueItems": false}},
+		}
+	}
+
+	tests := []createJSONSchemaRecursiveTest{
+		{reflect.TypeOf(testWalkComment{}), 0,
+			BsonM{"text": BsonM{"bsonType": []string{"string"}}},
+			[]string{"replies", "parent", "byUser"}},
+		// Negative depths are the same as 0
+		{reflect.TypeOf(testWalkComment{}), -1,
+			BsonM{"text": BsonM{"bsonType": []string{"string"}}},
+			[]string{"replies", "parent", "byUser"}},
+		{reflect.TypeOf(testWalkComment{}), 1, commentProps(comment(commentProps(comment(nil)))), []string{}},
+		{reflect.TypeOf(testWalkOrg{}), 0, BsonM{
+			"name": BsonM{"bsonType": []string{"string"}},
+			"units": BsonM{
+				"bsonType": []string{"array"},
+				"items": BsonM{
+					"bsonType":   []string{"object"},
+					"properties": BsonM{"name": BsonM{"bsonType": []string{"string"}}},
+					"required":   []string{}},
+				"uniqueItems": false},
+		}, []string{"units.org"}},
+	}
+
+	for _, test := range tests {
+		have, _, errs := createTestSchema(test.arg, Options{MaxRecursion: test.arg2})
+
+		havePaths := []string{}
+		for _, err := range errs {
+			havePaths = append(havePaths, err.(ErrorWithTag).Path())
+		}
+		if !reflect.DeepEqual(have, test.want) || !reflect.DeepEqual(havePaths, test.wantPaths) {
+			t.Errorf("Type: %v, %v;\nGot: %#v, %#v;\nWant: %#v, %#v;", test.arg, test.arg2, have, havePaths, test.want, test.wantPaths)
+		}
+	}
+}
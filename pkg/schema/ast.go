@@ -0,0 +1,39 @@
+package schema
+
+import (
+	"reflect"
+
+	"github.com/s-augustovitko/mongo-schema-go/pkg/schema/jsonschema"
+)
+
+// Typed model of a $jsonSchema, see Build to create it from a struct and Parse to read it from a map
+// ToBsonM converts it back to the map used by the driver, and it is encoded to json as that map
+type Schema = jsonschema.Schema
+
+// Value of additionalProperties or additionalItems, a schema or a boolean if the schema is nil
+type BoolOrSchema = jsonschema.BoolOrSchema
+
+// Creates an optional value of the Schema (eg. Schema{MaxLength: CreateVal(64)}), optional values that are not set are not written
+func CreateVal[T any](val T) jsonschema.Optional[T] {
+	return jsonschema.CreateVal(val)
+}
+
+// Parses a $jsonSchema map into the typed model, keywords that are not part of the model are kept in Schema.Extra
+func Parse(jsonSchema map[string]interface{}) (*Schema, error) {
+	return jsonschema.Parse(jsonSchema)
+}
+
+// Builds the typed $jsonSchema of the type T, same as For without the validator wrapper
+func Build[T any](opts ...Option) (out *Schema, warnings []error, err error) {
+	return BuildType(reflect.TypeOf((*T)(nil)).Elem(), opts...)
+}
+
+// Builds the typed $jsonSchema of a struct type or a pointer to a struct type, same as MarshalType without the validator wrapper
+// Returns a NotStructError if typ is not a struct
+func BuildType(typ reflect.Type, opts ...Option) (out *Schema, warnings []error, err error) {
+	out, warnings, err = buildType(typ, newOptions(opts...))
+	if err != nil {
+		return nil, warnings, err
+	}
+	return out, warnings, nil
+}
@@ -0,0 +1,64 @@
+package schema
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type buildTestItem struct {
+	Name string `bson:"name" validation:"required,min=1"`
+}
+
+type buildTest struct {
+	Items []buildTestItem `bson:"items" validation:"max=5"`
+	Count uint8           `bson:"count"`
+}
+
+func TestBuild(t *testing.T) {
+	want := &Schema{
+		BsonType:             []string{"object"},
+		Title:                CreateVal("Build"),
+		AdditionalProperties: &BoolOrSchema{Allow: false},
+		Required:             []string{},
+		Properties: map[string]*Schema{
+			"_id": {BsonType: []string{"objectId"}},
+			"items": {
+				BsonType:    []string{"array"},
+				MaxItems:    CreateVal(5),
+				UniqueItems: CreateVal(false),
+				Items: &Schema{
+					BsonType: []string{"object"},
+					Required: []string{"name"},
+					Properties: map[string]*Schema{
+						"name": {BsonType: []string{"string"}, MinLength: CreateVal(1)},
+					},
+				},
+			},
+			"count": {BsonType: []string{"int"}, Minimum: CreateVal(0.0), Maximum: CreateVal(255.0)},
+		},
+	}
+
+	have, warnings, err := Build[buildTest](Title("Build"), AdditionalProperties(false))
+	if err != nil || len(warnings) > 0 || !reflect.DeepEqual(have, want) {
+		t.Errorf("\nGot: %#v;\nWant: %#v;\nWarnings: %v;\nError: %v", have, want, warnings, err)
+	}
+
+	_, _, err = BuildType(reflect.TypeOf(1))
+	if !errors.As(err, &NotStructError{}) {
+		t.Errorf("\nGot: %#v;\nWant: %#v", err, NotStructError{Type: reflect.TypeOf(1)})
+	}
+}
+
+func TestParse(t *testing.T) {
+	jsonSchema := map[string]interface{}{
+		"bsonType":   "object",
+		"properties": map[string]interface{}{"a": map[string]interface{}{"bsonType": []string{"int"}}},
+	}
+	want := &Schema{BsonType: []string{"object"}, Properties: map[string]*Schema{"a": {BsonType: []string{"int"}}}}
+
+	have, err := Parse(jsonSchema)
+	if err != nil || !reflect.DeepEqual(have, want) {
+		t.Errorf("\nGot: %#v;\nWant: %#v;\nError: %v", have, want, err)
+	}
+}
@@ -0,0 +1,462 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/s-augustovitko/mongo-schema-go/internal/tags"
+	"github.com/s-augustovitko/mongo-schema-go/internal/validation"
+	"github.com/s-augustovitko/mongo-schema-go/pkg/schema/jsonschema"
+)
+
+// Builds a schema without struct tags, eg.
+// schema.Object().Prop("email", schema.String().Pattern("^.+@.+$").Required())
+// methods change the schema of the builder and return it, so they can be chained
+type Builder struct {
+	schema   *Schema
+	required jsonschema.Optional[bool]
+	errs     []error
+	children []*Builder
+	// Updates the required properties of the parent of a builder from Prop or Property
+	setRequired func(required bool)
+	// A builder is part of the schema it is added to, so it can only be added once
+	adopted bool
+}
+
+func newBuilder(schema *Schema) *Builder {
+	return &Builder{schema: schema}
+}
+
+// Creates a builder from a schema (eg. the output of Build or Parse), the schema is changed by the builder
+func From(schema *Schema) *Builder {
+	if schema == nil {
+		schema = &Schema{}
+	}
+	return newBuilder(schema)
+}
+
+// Creates a builder from the reflected schema of the type T, so its properties can be overridden
+func Reflect[T any](opts ...Option) (out *Builder, warnings []error, err error) {
+	return ReflectType(reflect.TypeOf((*T)(nil)).Elem(), opts...)
+}
+
+// Creates a builder from the reflected schema of a struct type, same as Reflect with a reflect.Type
+func ReflectType(typ reflect.Type, opts ...Option) (out *Builder, warnings []error, err error) {
+	schema, warnings, err := BuildType(typ, opts...)
+	if err != nil {
+		return nil, warnings, err
+	}
+	return From(schema), warnings, nil
+}
+
+// Creates a schema of the bson types, the types are checked when the schema is created
+func Type(types ...string) *Builder {
+	b := newBuilder(&Schema{})
+	if _, err := tags.ParseTypes(strings.Join(types, ",")); err != nil || len(types) == 0 {
+		b.addError(fmt.Errorf("invalid bson types %v", types))
+	}
+	b.schema.BsonType = append([]string{}, types...)
+	return b
+}
+
+// Creates an object schema without properties, properties are added with Prop
+func Object() *Builder {
+	b := Type("object")
+	b.schema.Properties = map[string]*Schema{}
+	b.schema.Required = []string{}
+	return b
+}
+
+// Creates an array schema, items is the schema of every item and can be nil
+func Array(items *Builder) *Builder {
+	return Type("array").Items(items)
+}
+
+// Creates a schema of a single bson type
+func String() *Builder   { return Type("string") }
+func Int() *Builder      { return Type("int") }
+func Long() *Builder     { return Type("long") }
+func Double() *Builder   { return Type("double") }
+func Decimal() *Builder  { return Type("decimal") }
+func Bool() *Builder     { return Type("bool") }
+func Date() *Builder     { return Type("date") }
+func ObjectID() *Builder { return Type("objectId") }
+
+// Creates a schema that accepts any value
+func Any() *Builder {
+	return newBuilder(&Schema{})
+}
+
+func (b *Builder) addError(err error) {
+	b.errs = append(b.errs, err)
+}
+
+// Keeps the child so its errors are returned by the builder, even the ones after it is added
+// the changes of the child after it is added are part of the schema, so a child that is already added to a schema is an error
+func (b *Builder) adopt(child *Builder) *Schema {
+	if child.adopted {
+		b.addError(fmt.Errorf("the builder is already added to a schema, create a new builder to add it again"))
+		return child.schema.Copy()
+	}
+	child.adopted = true
+	b.children = append(b.children, child)
+	return child.schema
+}
+
+// Gets the first error of the builder or its children
+func (b *Builder) err() error {
+	if len(b.errs) > 0 {
+		return b.errs[0]
+	}
+	for _, child := range b.children {
+		if err := child.err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Gets the schema, returns an error if the builder was used with invalid values
+func (b *Builder) Schema() (*Schema, error) {
+	return b.schema, b.err()
+}
+
+// Gets the validator of the schema with the same shape as Marshal, the root must be an object
+// the root has the default title and allows additional properties unless the schema sets them, options set with
+// Title, AdditionalProperties, Strict, InheritAdditionalProperties and ValidationLevel take precedence over the schema
+// the options that change how structs are walked (eg. NamingPolicy or Nullable) do not change a built schema
+// when the object does not allow additional properties, _id is allowed since the driver adds it to the documents without one
+func (b *Builder) Marshal(opts ...Option) (validation.BsonM, error) {
+	schema, err := b.Schema()
+	if err != nil {
+		return nil, err
+	}
+	if len(schema.BsonType) > 0 && (len(schema.BsonType) != 1 || schema.BsonType[0] != "object") {
+		return nil, fmt.Errorf("the root of the validator must be an object, got the bson types %v", schema.BsonType)
+	}
+
+	options := newOptions(opts...)
+	jsonSchema := schema.Copy()
+	if options.Title.Exists || !jsonSchema.Title.Exists {
+		jsonSchema.Title = CreateVal(options.title())
+	}
+	if options.AdditionalProperties.Exists || jsonSchema.AdditionalProperties == nil {
+		jsonSchema.AdditionalProperties = &BoolOrSchema{Allow: options.additionalProperties()}
+	}
+	if options.InheritAdditionalProperties {
+		inheritAdditionalProperties(jsonSchema, options.additionalProperties())
+	}
+	validation.AllowID(jsonSchema)
+	return validator(jsonSchema, options), nil
+}
+
+// Sets additionalProperties to the nested objects with properties that do not set it, like the walk does for nested structs
+func inheritAdditionalProperties(schema *Schema, allow bool) {
+	nested := []*Schema{schema.Items, schema.Not}
+	nested = append(nested, schema.TupleItems...)
+	nested = append(nested, schema.OneOf...)
+	nested = append(nested, schema.AnyOf...)
+	nested = append(nested, schema.AllOf...)
+	for _, item := range schema.Properties {
+		nested = append(nested, item)
+	}
+	for _, item := range schema.PatternProperties {
+		nested = append(nested, item)
+	}
+	for _, item := range []*BoolOrSchema{schema.AdditionalProperties, schema.AdditionalItems} {
+		if item != nil {
+			nested = append(nested, item.Schema)
+		}
+	}
+
+	for _, item := range nested {
+		if item == nil {
+			continue
+		}
+		if item.Properties != nil && item.AdditionalProperties == nil {
+			item.AdditionalProperties = &BoolOrSchema{Allow: allow}
+		}
+		inheritAdditionalProperties(item, allow)
+	}
+}
+
+// Marks the schema as a required property of the object it is added to with Prop, before or after it is added
+func (b *Builder) Required() *Builder {
+	b.required = CreateVal(true)
+	if b.setRequired != nil {
+		b.setRequired(true)
+	}
+	return b
+}
+
+// Marks the schema as an optional property of the object it is added to with Prop, before or after it is added
+// without Required or Optional, a property that replaces another one keeps its required state
+func (b *Builder) Optional() *Builder {
+	b.required = CreateVal(false)
+	if b.setRequired != nil {
+		b.setRequired(false)
+	}
+	return b
+}
+
+// Adds "null" to the bson types, and nil to the enum if it has one since the enum is checked on its own
+func (b *Builder) Nullable() *Builder {
+	if !tags.Contains(b.schema.BsonType, "null") {
+		b.schema.BsonType = append(b.schema.BsonType, "null")
+	}
+	b.schema.Enum = validation.NullableEnum(b.schema.Enum, b.schema.BsonType)
+	return b
+}
+
+func (b *Builder) Title(title string) *Builder {
+	b.schema.Title = CreateVal(title)
+	return b
+}
+
+func (b *Builder) Description(description string) *Builder {
+	b.schema.Description = CreateVal(description)
+	return b
+}
+
+// Sets the values of the schema, nil is added if the bson types include "null"
+// without values the enum is removed, since MongoDB does not accept an empty enum
+func (b *Builder) Enum(values ...interface{}) *Builder {
+	b.schema.Enum = nil
+	if len(values) > 0 {
+		b.schema.Enum = validation.NullableEnum(append([]interface{}{}, values...), b.schema.BsonType)
+	}
+	return b
+}
+
+// OBJECTS
+
+// Adds or replaces a property of the object, a nil prop removes the property like RemoveProp
+func (b *Builder) Prop(name string, prop *Builder) *Builder {
+	if prop == nil {
+		return b.RemoveProp(name)
+	}
+	if b.schema.Properties == nil {
+		b.schema.Properties = map[string]*Schema{}
+	}
+	adopted := prop.adopted
+	b.schema.Properties[name] = b.adopt(prop)
+	if !adopted {
+		prop.setRequired = func(required bool) { b.requireProp(name, required) }
+	}
+
+	if prop.required.Exists {
+		b.requireProp(name, prop.required.Val)
+	}
+	return b
+}
+
+// Gets a builder of a property of the object so it can be changed, the property is created empty if it does not exist
+// Required and Optional of the property builder change the required properties of the object
+func (b *Builder) Property(name string) *Builder {
+	prop, ok := b.schema.Properties[name]
+	if !ok {
+		prop = &Schema{}
+		b.Prop(name, From(prop))
+	}
+	out := &Builder{schema: prop, setRequired: func(required bool) { b.requireProp(name, required) }, adopted: true}
+	b.children = append(b.children, out)
+	return out
+}
+
+// Removes a property of the object and from the required properties
+func (b *Builder) RemoveProp(name string) *Builder {
+	delete(b.schema.Properties, name)
+	b.requireProp(name, false)
+	return b
+}
+
+// Adds or removes name from the required properties of the object
+func (b *Builder) requireProp(name string, required bool) {
+	if b.schema.Required == nil {
+		b.schema.Required = []string{}
+	}
+
+	found := tags.Contains(b.schema.Required, name)
+	if required && !found {
+		b.schema.Required = append(b.schema.Required, name)
+	}
+	if !required && found {
+		out := []string{}
+		for _, item := range b.schema.Required {
+			if item != name {
+				out = append(out, item)
+			}
+		}
+		b.schema.Required = out
+	}
+}
+
+// Sets if the object allows properties that are not in its properties
+func (b *Builder) AdditionalProperties(allow bool) *Builder {
+	b.schema.AdditionalProperties = &BoolOrSchema{Allow: allow}
+	return b
+}
+
+// Sets the schema of the properties that are not in the properties of the object (eg. the values of a map)
+// nil values remove additionalProperties
+func (b *Builder) AdditionalPropertiesSchema(values *Builder) *Builder {
+	b.schema.AdditionalProperties = nil
+	if values != nil {
+		b.schema.AdditionalProperties = &BoolOrSchema{Allow: true, Schema: b.adopt(values)}
+	}
+	return b
+}
+
+// Sets the schema of the properties that match the pattern, nil values remove the pattern
+func (b *Builder) PatternProperties(pattern string, values *Builder) *Builder {
+	if values == nil {
+		delete(b.schema.PatternProperties, pattern)
+		return b
+	}
+	if b.schema.PatternProperties == nil {
+		b.schema.PatternProperties = map[string]*Schema{}
+	}
+	b.schema.PatternProperties[pattern] = b.adopt(values)
+	return b
+}
+
+func (b *Builder) MinProperties(min int) *Builder {
+	b.schema.MinProperties = CreateVal(min)
+	return b
+}
+
+func (b *Builder) MaxProperties(max int) *Builder {
+	b.schema.MaxProperties = CreateVal(max)
+	return b
+}
+
+// ARRAYS
+
+// Sets the schema of every item of the array, nil items accept any value
+func (b *Builder) Items(items *Builder) *Builder {
+	b.schema.TupleItems = nil
+	b.schema.Items = nil
+	if items != nil {
+		b.schema.Items = b.adopt(items)
+	}
+	if !b.schema.UniqueItems.Exists {
+		b.schema.UniqueItems = CreateVal(false)
+	}
+	return b
+}
+
+// Sets the schema of each position of the array, items after the last position are not allowed
+// like Items, a nil position accepts any value
+func (b *Builder) Tuple(items ...*Builder) *Builder {
+	b.schema.Items = nil
+	b.schema.TupleItems = make([]*Schema, 0, len(items))
+	for _, item := range items {
+		if item == nil {
+			item = Any()
+		}
+		b.schema.TupleItems = append(b.schema.TupleItems, b.adopt(item))
+	}
+	b.schema.AdditionalItems = &BoolOrSchema{Allow: false}
+	return b
+}
+
+func (b *Builder) MinItems(min int) *Builder {
+	b.schema.MinItems = CreateVal(min)
+	return b
+}
+
+func (b *Builder) MaxItems(max int) *Builder {
+	b.schema.MaxItems = CreateVal(max)
+	return b
+}
+
+func (b *Builder) UniqueItems() *Builder {
+	b.schema.UniqueItems = CreateVal(true)
+	return b
+}
+
+// NUMBERS
+
+func (b *Builder) Minimum(min float64) *Builder {
+	b.schema.Minimum = CreateVal(min)
+	b.schema.ExclusiveMinimum = false
+	return b
+}
+
+func (b *Builder) Maximum(max float64) *Builder {
+	b.schema.Maximum = CreateVal(max)
+	b.schema.ExclusiveMaximum = false
+	return b
+}
+
+func (b *Builder) ExclusiveMinimum(min float64) *Builder {
+	b.schema.Minimum = CreateVal(min)
+	b.schema.ExclusiveMinimum = true
+	return b
+}
+
+func (b *Builder) ExclusiveMaximum(max float64) *Builder {
+	b.schema.Maximum = CreateVal(max)
+	b.schema.ExclusiveMaximum = true
+	return b
+}
+
+func (b *Builder) MultipleOf(val float64) *Builder {
+	b.schema.MultipleOf = CreateVal(val)
+	return b
+}
+
+// STRINGS
+
+func (b *Builder) MinLength(min int) *Builder {
+	b.schema.MinLength = CreateVal(min)
+	return b
+}
+
+func (b *Builder) MaxLength(max int) *Builder {
+	b.schema.MaxLength = CreateVal(max)
+	return b
+}
+
+func (b *Builder) Pattern(pattern string) *Builder {
+	b.schema.Pattern = CreateVal(pattern)
+	return b
+}
+
+// COMBINATORS
+// The schemas replace the ones set before, without schemas (or with a nil one for Not) the combinator is removed
+
+func (b *Builder) OneOf(schemas ...*Builder) *Builder {
+	b.schema.OneOf = b.adoptAll(schemas)
+	return b
+}
+
+func (b *Builder) AnyOf(schemas ...*Builder) *Builder {
+	b.schema.AnyOf = b.adoptAll(schemas)
+	return b
+}
+
+func (b *Builder) AllOf(schemas ...*Builder) *Builder {
+	b.schema.AllOf = b.adoptAll(schemas)
+	return b
+}
+
+func (b *Builder) Not(schema *Builder) *Builder {
+	b.schema.Not = nil
+	if schema != nil {
+		b.schema.Not = b.adopt(schema)
+	}
+	return b
+}
+
+// Gets the schemas of a combinator, nil schemas are skipped and no schemas is nil since combinators can not be empty
+func (b *Builder) adoptAll(schemas []*Builder) []*Schema {
+	var out []*Schema
+	for _, item := range schemas {
+		if item != nil {
+			out = append(out, b.adopt(item))
+		}
+	}
+	return out
+}
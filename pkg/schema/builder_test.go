@@ -0,0 +1,306 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/s-augustovitko/mongo-schema-go/internal/validation"
+)
+
+func TestBuilder(t *testing.T) {
+	have, err := Object().
+		Title("Users").
+		AdditionalProperties(false).
+		Prop("email", String().Pattern("^.+@.+$").Required()).
+		Prop("age", Int().Minimum(0).ExclusiveMaximum(150).Nullable()).
+		Prop("tags", Array(String().MaxLength(20)).MaxItems(5).UniqueItems()).
+		Prop("point", Array(nil).Tuple(Double(), Double())).
+		Prop("labels", Object().PatternProperties("^[a-z]+$", String()).AdditionalProperties(false)).
+		Prop("kind", String().Enum("a", "b").Required()).
+		Prop("kind", String().Enum("a", "b", "c")).
+		Prop("payload", Any().OneOf(Object().Prop("a", Int()), Object().Prop("b", Bool())).Not(Date())).
+		Marshal()
+	want := validation.BsonM{"validator": validation.BsonM{"$jsonSchema": validation.BsonM{
+		"bsonType":             "object",
+		"title":                "Users",
+		"additionalProperties": false,
+		"required":             []string{"email", "kind"},
+		"properties": validation.BsonM{
+			"_id":   validation.BsonM{"bsonType": []string{"objectId"}},
+			"email": validation.BsonM{"bsonType": []string{"string"}, "pattern": "^.+@.+$"},
+			"age":   validation.BsonM{"bsonType": []string{"int", "null"}, "minimum": float64(0), "maximum": float64(150), "exclusiveMaximum": true},
+			"tags": validation.BsonM{"bsonType": []string{"array"}, "items": validation.BsonM{"bsonType": []string{"string"}, "maxLength": 20},
+				"maxItems": 5, "uniqueItems": true},
+			"point": validation.BsonM{"bsonType": []string{"array"}, "uniqueItems": false, "additionalItems": false,
+				"items": []validation.BsonM{{"bsonType": []string{"double"}}, {"bsonType": []string{"double"}}}},
+			"labels": validation.BsonM{"bsonType": []string{"object"}, "properties": validation.BsonM{}, "required": []string{},
+				"patternProperties": validation.BsonM{"^[a-z]+$": validation.BsonM{"bsonType": []string{"string"}}}, "additionalProperties": false},
+			"kind": validation.BsonM{"bsonType": []string{"string"}, "enum": []interface{}{"a", "b", "c"}},
+			"payload": validation.BsonM{
+				"oneOf": []validation.BsonM{
+					{"bsonType": []string{"object"}, "properties": validation.BsonM{"a": validation.BsonM{"bsonType": []string{"int"}}}, "required": []string{}},
+					{"bsonType": []string{"object"}, "properties": validation.BsonM{"b": validation.BsonM{"bsonType": []string{"bool"}}}, "required": []string{}},
+				},
+				"not": validation.BsonM{"bsonType": []string{"date"}}},
+		},
+	}}}
+
+	if err != nil || !reflect.DeepEqual(have, want) {
+		t.Errorf("\nGot: %#v;\nWant: %#v;\nError: %v", have, want, err)
+	}
+}
+
+func TestBuilderReflect(t *testing.T) {
+	b, warnings, err := Reflect[buildTest](AdditionalProperties(false))
+	if err != nil || len(warnings) > 0 {
+		t.Fatalf("\nWarnings: %v;\nError: %v", warnings, err)
+	}
+
+	b.Prop("count", Long().Required()).
+		Prop("note", String()).
+		RemoveProp("_id")
+	b.Property("items").MaxItems(10).Required()
+	b.Property("items").Optional()
+	b.Property("extra").Description("Created by Property")
+
+	have, err := b.Schema()
+	want := &Schema{
+		BsonType:             []string{"object"},
+		Title:                CreateVal(defaultTitle),
+		AdditionalProperties: &BoolOrSchema{Allow: false},
+		Required:             []string{"count"},
+		Properties: map[string]*Schema{
+			"items": {
+				BsonType:    []string{"array"},
+				MaxItems:    CreateVal(10),
+				UniqueItems: CreateVal(false),
+				Items: &Schema{
+					BsonType:   []string{"object"},
+					Required:   []string{"name"},
+					Properties: map[string]*Schema{"name": {BsonType: []string{"string"}, MinLength: CreateVal(1)}},
+				},
+			},
+			"count": {BsonType: []string{"long"}},
+			"note":  {BsonType: []string{"string"}},
+			"extra": {Description: CreateVal("Created by Property")},
+		},
+	}
+
+	if err != nil || !reflect.DeepEqual(have, want) {
+		t.Errorf("\nGot: %#v;\nWant: %#v;\nError: %v", have, want, err)
+	}
+
+	// _id is allowed again since additional properties are not
+	validator, err := b.Marshal()
+	properties := validator["validator"].(validation.BsonM)["$jsonSchema"].(validation.BsonM)["properties"].(validation.BsonM)
+	if _, ok := properties["_id"]; err != nil || !ok {
+		t.Errorf("\nGot: %#v;\nWant: _id;\nError: %v", properties, err)
+	}
+}
+
+type builderMarshalTest struct {
+	arg1 *Builder
+	arg2 []Option
+	want validation.BsonM
+}
+
+func TestBuilderMarshal(t *testing.T) {
+	tests := []builderMarshalTest{
+		{Object(), nil, validation.BsonM{"validator": validation.BsonM{"$jsonSchema": validation.BsonM{
+			"bsonType": "object", "title": "Schema Validation", "additionalProperties": true, "properties": validation.BsonM{}, "required": []string{}}}}},
+		{Any().Title("Users").AdditionalProperties(false), nil, validation.BsonM{"validator": validation.BsonM{"$jsonSchema": validation.BsonM{
+			"bsonType": "object", "title": "Users", "additionalProperties": false}}}},
+		{Object().Title("Users"), []Option{Title("Accounts"), Strict(), ValidationLevel(LevelModerate), NamingPolicy(NamingSnakeCase)}, validation.BsonM{
+			"validationLevel": "moderate",
+			"validator": validation.BsonM{"$jsonSchema": validation.BsonM{
+				"bsonType": "object", "title": "Accounts", "additionalProperties": false, "required": []string{},
+				"properties": validation.BsonM{"_id": validation.BsonM{"bsonType": []string{"objectId"}}}}}}},
+		{Object().Prop("address", Object().Prop("zip", String())).Prop("tags", Array(Object())).Prop("meta", Object().AdditionalProperties(true)),
+			[]Option{InheritAdditionalProperties()}, validation.BsonM{"validator": validation.BsonM{"$jsonSchema": validation.BsonM{
+				"bsonType": "object", "title": "Schema Validation", "additionalProperties": true, "required": []string{},
+				"properties": validation.BsonM{
+					"address": validation.BsonM{"bsonType": []string{"object"}, "additionalProperties": true, "required": []string{},
+						"properties": validation.BsonM{"zip": validation.BsonM{"bsonType": []string{"string"}}}},
+					"tags": validation.BsonM{"bsonType": []string{"array"}, "uniqueItems": false, "items": validation.BsonM{
+						"bsonType": []string{"object"}, "additionalProperties": true, "properties": validation.BsonM{}, "required": []string{}}},
+					"meta": validation.BsonM{"bsonType": []string{"object"}, "additionalProperties": true, "properties": validation.BsonM{}, "required": []string{}}}}}}},
+		{Object().Prop("address", Object()).Prop("meta", Object().AdditionalProperties(true)), []Option{Strict()},
+			validation.BsonM{"validator": validation.BsonM{"$jsonSchema": validation.BsonM{
+				"bsonType": "object", "title": "Schema Validation", "additionalProperties": false, "required": []string{},
+				"properties": validation.BsonM{
+					"_id":     validation.BsonM{"bsonType": []string{"objectId"}},
+					"address": validation.BsonM{"bsonType": []string{"object"}, "additionalProperties": false, "properties": validation.BsonM{}, "required": []string{}},
+					"meta":    validation.BsonM{"bsonType": []string{"object"}, "additionalProperties": true, "properties": validation.BsonM{}, "required": []string{}}}}}}},
+	}
+
+	for _, test := range tests {
+		have, err := test.arg1.Marshal(test.arg2...)
+		if err != nil || !reflect.DeepEqual(have, test.want) {
+			t.Errorf("\nGot: %#v;\nWant: %#v;\nError: %v", have, test.want, err)
+		}
+	}
+}
+
+// Reflected schemas are written like Marshal writes them
+func TestBuilderMarshalReflect(t *testing.T) {
+	b, _, err := Reflect[buildTest](Title("Build"), Strict())
+	if err != nil {
+		t.Fatalf("\nError: %v", err)
+	}
+
+	have, err := b.Marshal(ValidationLevel(LevelStrict))
+	want, _, _ := For[buildTest](Title("Build"), Strict(), ValidationLevel(LevelStrict))
+	if err != nil || !reflect.DeepEqual(have, want) {
+		t.Errorf("\nGot: %#v;\nWant: %#v;\nError: %v", have, want, err)
+	}
+
+	// The options do not change the schema of the builder
+	if schema, _ := b.Schema(); schema.BsonType[0] != "object" || schema.Title.Val != "Build" {
+		t.Errorf("\nGot: %#v;\nWant: the reflected schema", schema)
+	}
+}
+
+func TestBuilderNullableEnum(t *testing.T) {
+	tests := []*Builder{
+		String().Enum("a", "b").Nullable(),
+		String().Nullable().Enum("a", "b"),
+		String().Nullable().Enum("a", nil, "b"),
+	}
+	want := []interface{}{"a", "b", nil}
+
+	for i, test := range tests[:2] {
+		if have, _ := test.Schema(); !reflect.DeepEqual(have.Enum, want) {
+			t.Errorf("\nTest: %v;\nGot: %#v;\nWant: %#v", i, have.Enum, want)
+		}
+	}
+	if have, _ := tests[2].Schema(); !reflect.DeepEqual(have.Enum, []interface{}{"a", nil, "b"}) {
+		t.Errorf("\nGot: %#v;\nWant: %#v", have.Enum, []interface{}{"a", nil, "b"})
+	}
+}
+
+type builderNilTest struct {
+	arg  *Builder
+	want validation.BsonM
+}
+
+func TestBuilderNil(t *testing.T) {
+	tests := []builderNilTest{
+		{Object().Prop("a", String().Required()).Prop("a", nil).Prop("b", nil), validation.BsonM{
+			"bsonType": []string{"object"}, "properties": validation.BsonM{}, "required": []string{}}},
+		{Any().AdditionalPropertiesSchema(String()).AdditionalPropertiesSchema(nil), validation.BsonM{}},
+		{Any().PatternProperties("^a", String()).PatternProperties("^b", Int()).PatternProperties("^a", nil).PatternProperties("^c", nil),
+			validation.BsonM{"patternProperties": validation.BsonM{"^b": validation.BsonM{"bsonType": []string{"int"}}}}},
+		{Any().Tuple(Double(), nil), validation.BsonM{
+			"items":           []validation.BsonM{{"bsonType": []string{"double"}}, {}},
+			"additionalItems": false}},
+		{String().Enum("a").Enum(), validation.BsonM{"bsonType": []string{"string"}}},
+		{String().Enum().Nullable(), validation.BsonM{"bsonType": []string{"string", "null"}}},
+	}
+
+	for _, test := range tests {
+		schema, err := test.arg.Schema()
+		if have := schema.ToBsonM(); err != nil || !reflect.DeepEqual(have, test.want) {
+			t.Errorf("\nGot: %#v;\nWant: %#v;\nError: %v", have, test.want, err)
+		}
+	}
+}
+
+// Required and Optional change the object the property is added to, before or after it is added
+func TestBuilderRequired(t *testing.T) {
+	before := String().Required()
+	after := String()
+	optional := String().Required()
+	obj := Object().Prop("before", before).Prop("after", after).Prop("optional", optional)
+	after.Required()
+	optional.Optional()
+
+	schema, err := obj.Schema()
+	if want := []string{"before", "after"}; err != nil || !reflect.DeepEqual(schema.Required, want) {
+		t.Errorf("\nGot: %#v;\nWant: %#v;\nError: %v", schema.Required, want, err)
+	}
+}
+
+// A builder is part of the schema it is added to, so it can not be added twice
+func TestBuilderAddedTwice(t *testing.T) {
+	name, items, address := String().MaxLength(64), String(), Object()
+	tests := []*Builder{
+		Object().Prop("first", name).Prop("last", name),
+		Object().Prop("tags", Array(items)).Prop("labels", Array(items)),
+		Object().Prop("street", address.Property("street")),
+	}
+
+	for _, test := range tests {
+		if _, err := test.Schema(); err == nil {
+			t.Errorf("\nGot: %#v;\nWant: error", err)
+		}
+	}
+}
+
+type builderCombinatorsTest struct {
+	arg  *Builder
+	want validation.BsonM
+}
+
+func TestBuilderCombinators(t *testing.T) {
+	tests := []builderCombinatorsTest{
+		{Object().AnyOf(From(&Schema{Required: []string{"email"}}), From(&Schema{Required: []string{"phone"}})), validation.BsonM{
+			"bsonType": []string{"object"}, "properties": validation.BsonM{}, "required": []string{},
+			"anyOf": []validation.BsonM{{"required": []string{"email"}}, {"required": []string{"phone"}}}}},
+		{String().Not(Any().Enum("")), validation.BsonM{"bsonType": []string{"string"}, "not": validation.BsonM{"enum": []interface{}{""}}}},
+		{String().AllOf(Any().MinLength(1), nil, Any().Pattern("^[a-z]+$")), validation.BsonM{
+			"bsonType": []string{"string"},
+			"allOf":    []validation.BsonM{{"minLength": 1}, {"pattern": "^[a-z]+$"}}}},
+		{Any().OneOf(Int(), Bool()).OneOf(String()), validation.BsonM{"oneOf": []validation.BsonM{{"bsonType": []string{"string"}}}}},
+		{Any().OneOf(Int()).AnyOf(Int()).AllOf(Int()).Not(Int()).OneOf().AnyOf(nil).AllOf().Not(nil), validation.BsonM{}},
+	}
+
+	for _, test := range tests {
+		schema, err := test.arg.Schema()
+		if have := schema.ToBsonM(); err != nil || !reflect.DeepEqual(have, test.want) {
+			t.Errorf("\nGot: %#v;\nWant: %#v;\nError: %v", have, test.want, err)
+		}
+	}
+
+	// The combinators of the root are written in the validator
+	have, err := Object().AnyOf(Object().Prop("email", String().Required()), Object().Prop("phone", String().Required())).Marshal()
+	anyOf := have["validator"].(validation.BsonM)["$jsonSchema"].(validation.BsonM)["anyOf"]
+	want := []validation.BsonM{
+		{"bsonType": []string{"object"}, "properties": validation.BsonM{"email": validation.BsonM{"bsonType": []string{"string"}}}, "required": []string{"email"}},
+		{"bsonType": []string{"object"}, "properties": validation.BsonM{"phone": validation.BsonM{"bsonType": []string{"string"}}}, "required": []string{"phone"}},
+	}
+	if err != nil || !reflect.DeepEqual(anyOf, want) {
+		t.Errorf("\nGot: %#v;\nWant: %#v;\nError: %v", anyOf, want, err)
+	}
+
+	// Errors of the schemas of the combinators are returned by the builder
+	if _, err := Any().OneOf(Int(), Type("unknown")).Schema(); err == nil {
+		t.Errorf("\nGot: %#v;\nWant: error", err)
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+	tests := []*Builder{
+		Type("unknown"),
+		Type(),
+		Object().Prop("a", Type("string", "unknown")),
+		Array(Type("unknown")),
+		Object().Prop("a", Object().Prop("b", Type("unknown"))),
+		String(),
+		Type("object", "null"),
+	}
+
+	for _, test := range tests {
+		if _, err := test.Marshal(); err == nil {
+			t.Errorf("\nGot: %#v;\nWant: error", err)
+		}
+	}
+
+	// Errors of a child after it is added are returned by the parent
+	grandchild := Object()
+	child := Object().Prop("grandchild", grandchild)
+	parent := Object().Prop("child", child)
+	grandchild.Prop("a", Type("unknown"))
+	if _, err := parent.Schema(); err == nil {
+		t.Errorf("\nGot: %#v;\nWant: error", err)
+	}
+}
@@ -0,0 +1,19 @@
+package schema
+
+import (
+	"reflect"
+
+	"github.com/s-augustovitko/mongo-schema-go/internal/validation"
+)
+
+// Registers a schema that can be referenced by name in the oneOf, anyOf, allOf and not tags
+// eg. RegisterFragment("nonEmpty", map[string]interface{}{"minLength": 1})
+func RegisterFragment(name string, fragment map[string]interface{}) error {
+	return validation.RegisterFragment(name, fragment)
+}
+
+// Registers a type that can be referenced by name in the oneOf, anyOf, allOf and not tags
+// the type is described with its reflected schema, eg. RegisterFragmentType("card", reflect.TypeOf(CardPayment{}))
+func RegisterFragmentType(name string, typ reflect.Type) error {
+	return validation.RegisterFragmentType(name, typ)
+}
@@ -0,0 +1,304 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// Parses a $jsonSchema map, keywords that are not part of the model are kept in Extra
+// nested schemas are maps and lists can be any go slice or array (eg. []interface{}, []string, []int)
+// numbers can be of any int, uint or float kind or json.Number, the values are copied so the map can be changed after
+func Parse(obj map[string]interface{}) (*Schema, error) {
+	return parseSchema(obj, "")
+}
+
+// Parses a json object, integer numbers of the enum and the extra keywords are converted to int and the others to float64
+func ParseJSON(data []byte) (*Schema, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var obj map[string]interface{}
+	if err := decoder.Decode(&obj); err != nil {
+		return nil, fmt.Errorf("invalid json object, %v", err)
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("invalid json object, unexpected data after the object")
+	}
+	if obj == nil {
+		return nil, fmt.Errorf("invalid json object, got null")
+	}
+	return Parse(jsonNumbers(obj).(map[string]interface{}))
+}
+
+// Converts the json.Number values to int or float64
+func jsonNumbers(val interface{}) interface{} {
+	switch item := val.(type) {
+	case map[string]interface{}:
+		for k, v := range item {
+			item[k] = jsonNumbers(v)
+		}
+	case []interface{}:
+		for i, v := range item {
+			item[i] = jsonNumbers(v)
+		}
+	case json.Number:
+		if intVal, err := item.Int64(); err == nil {
+			return int(intVal)
+		}
+		floatVal, _ := item.Float64()
+		return floatVal
+	}
+	return val
+}
+
+func parseSchema(obj map[string]interface{}, path string) (*Schema, error) {
+	var err error
+	out := &Schema{}
+
+	// Sorted so the errors do not depend on the order of the map
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		val := obj[key]
+		keyPath := joinPath(path, key)
+
+		switch key {
+		case "bsonType":
+			out.BsonType, err = parseStrings(val, keyPath)
+		case "title":
+			out.Title, err = parseVal[string](val, keyPath)
+		case "description":
+			out.Description, err = parseVal[string](val, keyPath)
+		case "pattern":
+			out.Pattern, err = parseVal[string](val, keyPath)
+		case "enum":
+			var enum []interface{}
+			enum, err = parseList(val, keyPath)
+			if err == nil {
+				out.Enum = copyValue(enum).([]interface{})
+			}
+		// OBJECTS
+		case "properties":
+			out.Properties, err = parseSchemaMap(val, keyPath)
+		case "patternProperties":
+			out.PatternProperties, err = parseSchemaMap(val, keyPath)
+		case "additionalProperties":
+			out.AdditionalProperties, err = parseBoolOrSchema(val, keyPath)
+		case "required":
+			out.Required, err = parseStrings(val, keyPath)
+		case "minProperties":
+			out.MinProperties, err = parseInt(val, keyPath)
+		case "maxProperties":
+			out.MaxProperties, err = parseInt(val, keyPath)
+		// ARRAYS
+		case "items":
+			if item, ok := val.(map[string]interface{}); ok {
+				out.Items, err = parseSchema(item, keyPath)
+			} else {
+				out.TupleItems, err = parseSchemaList(val, keyPath)
+			}
+		case "additionalItems":
+			out.AdditionalItems, err = parseBoolOrSchema(val, keyPath)
+		case "minItems":
+			out.MinItems, err = parseInt(val, keyPath)
+		case "maxItems":
+			out.MaxItems, err = parseInt(val, keyPath)
+		case "uniqueItems":
+			out.UniqueItems, err = parseVal[bool](val, keyPath)
+		// NUMBERS
+		case "minimum":
+			out.Minimum, err = parseFloat(val, keyPath)
+		case "maximum":
+			out.Maximum, err = parseFloat(val, keyPath)
+		case "exclusiveMinimum":
+			var exclusive Optional[bool]
+			exclusive, err = parseVal[bool](val, keyPath)
+			out.ExclusiveMinimum = exclusive.Val
+		case "exclusiveMaximum":
+			var exclusive Optional[bool]
+			exclusive, err = parseVal[bool](val, keyPath)
+			out.ExclusiveMaximum = exclusive.Val
+		case "multipleOf":
+			out.MultipleOf, err = parseFloat(val, keyPath)
+		// STRINGS
+		case "minLength":
+			out.MinLength, err = parseInt(val, keyPath)
+		case "maxLength":
+			out.MaxLength, err = parseInt(val, keyPath)
+		// COMBINATORS
+		case "oneOf":
+			out.OneOf, err = parseSchemaList(val, keyPath)
+		case "anyOf":
+			out.AnyOf, err = parseSchemaList(val, keyPath)
+		case "allOf":
+			out.AllOf, err = parseSchemaList(val, keyPath)
+		case "not":
+			item, ok := val.(map[string]interface{})
+			if !ok {
+				return nil, parseError(keyPath, "a schema", val)
+			}
+			out.Not, err = parseSchema(item, keyPath)
+		default:
+			if out.Extra == nil {
+				out.Extra = map[string]interface{}{}
+			}
+			out.Extra[key] = copyValue(val)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func parseError(path, want string, val interface{}) error {
+	return fmt.Errorf("[%v]: expected %v, got [%#v]", path, want, val)
+}
+
+func parseVal[T any](val interface{}, path string) (Optional[T], error) {
+	item, ok := val.(T)
+	if !ok {
+		var zero T
+		return Optional[T]{}, parseError(path, fmt.Sprintf("a %T", zero), val)
+	}
+	return CreateVal(item), nil
+}
+
+// Gets the values of a list, any go slice or array is accepted (eg. []int, [3]string)
+func parseList(val interface{}, path string) ([]interface{}, error) {
+	if items, ok := val.([]interface{}); ok {
+		return items, nil
+	}
+
+	list := reflect.ValueOf(val)
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		return nil, parseError(path, "a list", val)
+	}
+	out := make([]interface{}, 0, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		out = append(out, list.Index(i).Interface())
+	}
+	return out, nil
+}
+
+// Gets a list of strings, a single string is a list with one value (eg. "bsonType": "object")
+func parseStrings(val interface{}, path string) ([]string, error) {
+	if item, ok := val.(string); ok {
+		return []string{item}, nil
+	}
+
+	items, err := parseList(val, path)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(items))
+	for i, item := range items {
+		str, ok := item.(string)
+		if !ok {
+			return nil, parseError(fmt.Sprintf("%v.%v", path, i), "a string", item)
+		}
+		out = append(out, str)
+	}
+	return out, nil
+}
+
+func parseSchemaList(val interface{}, path string) ([]*Schema, error) {
+	items, err := parseList(val, path)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*Schema, 0, len(items))
+	for i, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, parseError(fmt.Sprintf("%v.%v", path, i), "a schema", item)
+		}
+		schema, err := parseSchema(obj, fmt.Sprintf("%v.%v", path, i))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, schema)
+	}
+	return out, nil
+}
+
+func parseSchemaMap(val interface{}, path string) (map[string]*Schema, error) {
+	items, ok := val.(map[string]interface{})
+	if !ok {
+		return nil, parseError(path, "a map of schemas", val)
+	}
+
+	out := map[string]*Schema{}
+	for k, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, parseError(joinPath(path, k), "a schema", item)
+		}
+		schema, err := parseSchema(obj, joinPath(path, k))
+		if err != nil {
+			return nil, err
+		}
+		out[k] = schema
+	}
+	return out, nil
+}
+
+func parseBoolOrSchema(val interface{}, path string) (*BoolOrSchema, error) {
+	switch item := val.(type) {
+	case bool:
+		return &BoolOrSchema{Allow: item}, nil
+	case map[string]interface{}:
+		schema, err := parseSchema(item, path)
+		if err != nil {
+			return nil, err
+		}
+		return &BoolOrSchema{Allow: true, Schema: schema}, nil
+	}
+	return nil, parseError(path, "a bool or a schema", val)
+}
+
+// Gets a number of any int, uint or float kind, named numeric types are accepted as well
+func parseFloat(val interface{}, path string) (Optional[float64], error) {
+	if item, ok := val.(json.Number); ok {
+		if floatVal, err := item.Float64(); err == nil {
+			return CreateVal(floatVal), nil
+		}
+		return Optional[float64]{}, parseError(path, "a number", val)
+	}
+
+	num := reflect.ValueOf(val)
+	switch num.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return CreateVal(float64(num.Int())), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return CreateVal(float64(num.Uint())), nil
+	case reflect.Float32, reflect.Float64:
+		return CreateVal(num.Float()), nil
+	}
+	return Optional[float64]{}, parseError(path, "a number", val)
+}
+
+func parseInt(val interface{}, path string) (Optional[int], error) {
+	floatVal, err := parseFloat(val, path)
+	if err != nil || floatVal.Val != math.Trunc(floatVal.Val) || floatVal.Val < 0 {
+		return Optional[int]{}, parseError(path, "a non negative integer", val)
+	}
+	return CreateVal(int(floatVal.Val)), nil
+}
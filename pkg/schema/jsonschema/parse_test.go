@@ -0,0 +1,113 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type bsonM = map[string]interface{}
+
+type parseTest struct {
+	arg  bsonM
+	want *Schema
+}
+
+func TestParse(t *testing.T) {
+	tests := []parseTest{
+		{bsonM{}, &Schema{}},
+		{bsonM{"bsonType": "object", "required": []interface{}{"a"}, "additionalProperties": false},
+			&Schema{BsonType: []string{"object"}, Required: []string{"a"}, AdditionalProperties: &BoolOrSchema{}}},
+		{bsonM{"properties": bsonM{"a": bsonM{"bsonType": []string{"int"}, "minimum": 1, "maximum": json.Number("2.5"), "exclusiveMaximum": true}}},
+			&Schema{Properties: map[string]*Schema{"a": {BsonType: []string{"int"}, Minimum: CreateVal(1.0), Maximum: CreateVal(2.5), ExclusiveMaximum: true}}}},
+		{bsonM{"items": bsonM{"bsonType": "string", "maxLength": int64(3)}, "minItems": 1.0, "uniqueItems": false},
+			&Schema{Items: &Schema{BsonType: []string{"string"}, MaxLength: CreateVal(3)}, MinItems: CreateVal(1), UniqueItems: CreateVal(false)}},
+		{bsonM{"items": []interface{}{bsonM{"bsonType": "double"}}, "additionalItems": bsonM{"bsonType": "int"}},
+			&Schema{TupleItems: []*Schema{{BsonType: []string{"double"}}}, AdditionalItems: &BoolOrSchema{Allow: true, Schema: &Schema{BsonType: []string{"int"}}}}},
+		{bsonM{"oneOf": []bsonM{{"enum": []interface{}{"a", 1}}}, "not": bsonM{"pattern": "^a"}, "$comment": "kept"},
+			&Schema{OneOf: []*Schema{{Enum: []interface{}{"a", 1}}}, Not: &Schema{Pattern: CreateVal("^a")}, Extra: bsonM{"$comment": "kept"}}},
+		{bsonM{"title": "Title", "description": "Desc", "patternProperties": bsonM{"^a": bsonM{}}, "minProperties": 1},
+			&Schema{Title: CreateVal("Title"), Description: CreateVal("Desc"), PatternProperties: map[string]*Schema{"^a": {}}, MinProperties: CreateVal(1)}},
+		{bsonM{"minimum": uint8(1), "maximum": int8(-2), "multipleOf": float32(0.5), "maxLength": uint64(4), "enum": []int{1, 2}, "required": [1]string{"a"}},
+			&Schema{Minimum: CreateVal(1.0), Maximum: CreateVal(-2.0), MultipleOf: CreateVal(0.5), MaxLength: CreateVal(4), Enum: []interface{}{1, 2}, Required: []string{"a"}}},
+	}
+
+	for _, test := range tests {
+		have, err := Parse(test.arg)
+		if err != nil || !reflect.DeepEqual(have, test.want) {
+			t.Errorf("\nGot: %#v;\nWant: %#v;\nError: %v", have, test.want, err)
+		}
+	}
+}
+
+func TestParseCopy(t *testing.T) {
+	obj := bsonM{"enum": []interface{}{bsonM{"a": 1}}, "$comment": bsonM{"b": 2}}
+
+	have, err := Parse(obj)
+	if err != nil {
+		t.Fatalf("\nGot: %v;\nWant: no error", err)
+	}
+	obj["enum"].([]interface{})[0].(bsonM)["a"] = 3
+	obj["$comment"].(bsonM)["b"] = 4
+
+	want := &Schema{Enum: []interface{}{bsonM{"a": 1}}, Extra: bsonM{"$comment": bsonM{"b": 2}}}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("\nGot: %#v;\nWant: %#v", have, want)
+	}
+}
+
+type parseErrorTest struct {
+	arg  bsonM
+	want string
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []parseErrorTest{
+		{bsonM{"bsonType": 1}, "[bsonType]: expected a list, got [1]"},
+		{bsonM{"bsonType": []interface{}{"int", 2}}, "[bsonType.1]: expected a string, got [2]"},
+		{bsonM{"title": true}, "[title]: expected a string, got [true]"},
+		{bsonM{"properties": bsonM{"a": bsonM{"minLength": -1}}}, "[properties.a.minLength]: expected a non negative integer, got [-1]"},
+		{bsonM{"properties": bsonM{"a": "string"}}, "[properties.a]: expected a schema, got [\"string\"]"},
+		{bsonM{"items": []interface{}{bsonM{}, 1}}, "[items.1]: expected a schema, got [1]"},
+		{bsonM{"additionalProperties": "no"}, "[additionalProperties]: expected a bool or a schema, got [\"no\"]"},
+		{bsonM{"maxItems": 1.5}, "[maxItems]: expected a non negative integer, got [1.5]"},
+		{bsonM{"not": []interface{}{}}, "[not]: expected a schema, got [[]interface {}{}]"},
+		{bsonM{"minimum": "1"}, "[minimum]: expected a number, got [\"1\"]"},
+		{bsonM{"enum": "a"}, "[enum]: expected a list, got [\"a\"]"},
+	}
+
+	for _, test := range tests {
+		_, err := Parse(test.arg)
+		if err == nil || err.Error() != test.want {
+			t.Errorf("\nGot: %#v;\nWant: %#v", err, test.want)
+		}
+	}
+}
+
+type parseJSONTest struct {
+	arg     string
+	want    *Schema
+	wantErr bool
+}
+
+func TestParseJSON(t *testing.T) {
+	tests := []parseJSONTest{
+		{`{}`, &Schema{}, false},
+		{`{"minLength": 1, "maximum": 1.5}`, &Schema{MinLength: CreateVal(1), Maximum: CreateVal(1.5)}, false},
+		{`{"required": ["a"], "properties": {"a": {"enum": [1, "b", null]}}, "$comment": 2.5}`,
+			&Schema{Required: []string{"a"}, Properties: map[string]*Schema{"a": {Enum: []interface{}{1, "b", nil}}}, Extra: bsonM{"$comment": 2.5}}, false},
+		{`{"a": 1`, nil, true},
+		{`{"a": 1} {}`, nil, true},
+		{`[1]`, nil, true},
+		{`null`, nil, true},
+		{`{"minLength": "1"}`, nil, true},
+	}
+
+	for _, test := range tests {
+		have, err := ParseJSON([]byte(test.arg))
+		haveErr := err != nil
+		if !reflect.DeepEqual(have, test.want) || haveErr != test.wantErr {
+			t.Errorf("\nGot: %#v;\nWant: %#v;\nErr: %#v", have, test.want, err)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+// Package jsonschema is the typed model of a MongoDB $jsonSchema
+// it is created by the schema package from go types, and converted to the map used by the driver with ToBsonM
+package jsonschema
+
+import "encoding/json"
+
+// Optional value of a schema, values that do not exist are not written
+type Optional[T any] struct {
+	Exists bool
+	Val    T
+}
+
+// Creates an optional value that exists, eg. Schema{MaxLength: CreateVal(64)}
+func CreateVal[T any](val T) Optional[T] {
+	return Optional[T]{Val: val, Exists: true}
+}
+
+// Gets item if it exists, otherwise other
+func (item Optional[T]) Or(other Optional[T]) Optional[T] {
+	if item.Exists {
+		return item
+	}
+	return other
+}
+
+func (item Optional[T]) setVal(field string, obj map[string]interface{}) {
+	if item.Exists {
+		obj[field] = item.Val
+	}
+}
+
+// Typed model of a $jsonSchema, optional values that are not set are not written
+// nil slices and maps are not written either, empty ones are written as empty (eg. "required": [])
+type Schema struct {
+	BsonType    []string
+	Title       Optional[string]
+	Description Optional[string]
+	Enum        []interface{}
+	// OBJECTS
+	Properties           map[string]*Schema
+	PatternProperties    map[string]*Schema
+	AdditionalProperties *BoolOrSchema
+	Required             []string
+	MinProperties        Optional[int]
+	MaxProperties        Optional[int]
+	// ARRAYS
+	// Items is the schema of every item, TupleItems the schema of each position
+	Items           *Schema
+	TupleItems      []*Schema
+	AdditionalItems *BoolOrSchema
+	MinItems        Optional[int]
+	MaxItems        Optional[int]
+	UniqueItems     Optional[bool]
+	// NUMBERS
+	Minimum          Optional[float64]
+	Maximum          Optional[float64]
+	ExclusiveMinimum bool
+	ExclusiveMaximum bool
+	MultipleOf       Optional[float64]
+	// STRINGS
+	MinLength Optional[int]
+	MaxLength Optional[int]
+	Pattern   Optional[string]
+	// COMBINATORS
+	OneOf []*Schema
+	AnyOf []*Schema
+	AllOf []*Schema
+	Not   *Schema
+	// Keywords that are not part of the model, they are written as they are
+	Extra map[string]interface{}
+}
+
+// Value of additionalProperties or additionalItems, a schema or a boolean if the schema is nil
+type BoolOrSchema struct {
+	Allow  bool
+	Schema *Schema
+}
+
+// Converts the schema to the map used by the driver
+func (s *Schema) ToBsonM() map[string]interface{} {
+	out := map[string]interface{}{}
+	if s == nil {
+		return out
+	}
+	for k, v := range s.Extra {
+		out[k] = copyValue(v)
+	}
+
+	if s.BsonType != nil {
+		out["bsonType"] = append([]string{}, s.BsonType...)
+	}
+	s.Title.setVal("title", out)
+	s.Description.setVal("description", out)
+	if s.Enum != nil {
+		out["enum"] = copyValue(s.Enum)
+	}
+	// OBJECTS
+	if s.Properties != nil {
+		out["properties"] = schemaMapToBsonM(s.Properties)
+	}
+	if s.PatternProperties != nil {
+		out["patternProperties"] = schemaMapToBsonM(s.PatternProperties)
+	}
+	if s.AdditionalProperties != nil {
+		out["additionalProperties"] = s.AdditionalProperties.toValue()
+	}
+	if s.Required != nil {
+		out["required"] = append([]string{}, s.Required...)
+	}
+	s.MinProperties.setVal("minProperties", out)
+	s.MaxProperties.setVal("maxProperties", out)
+	// ARRAYS
+	if s.TupleItems != nil {
+		out["items"] = schemaSliceToBsonM(s.TupleItems)
+	} else if s.Items != nil {
+		out["items"] = s.Items.ToBsonM()
+	}
+	if s.AdditionalItems != nil {
+		out["additionalItems"] = s.AdditionalItems.toValue()
+	}
+	s.MinItems.setVal("minItems", out)
+	s.MaxItems.setVal("maxItems", out)
+	s.UniqueItems.setVal("uniqueItems", out)
+	// NUMBERS
+	s.Minimum.setVal("minimum", out)
+	s.Maximum.setVal("maximum", out)
+	if s.ExclusiveMinimum {
+		out["exclusiveMinimum"] = true
+	}
+	if s.ExclusiveMaximum {
+		out["exclusiveMaximum"] = true
+	}
+	s.MultipleOf.setVal("multipleOf", out)
+	// STRINGS
+	s.MinLength.setVal("minLength", out)
+	s.MaxLength.setVal("maxLength", out)
+	s.Pattern.setVal("pattern", out)
+	// COMBINATORS
+	if s.OneOf != nil {
+		out["oneOf"] = schemaSliceToBsonM(s.OneOf)
+	}
+	if s.AnyOf != nil {
+		out["anyOf"] = schemaSliceToBsonM(s.AnyOf)
+	}
+	if s.AllOf != nil {
+		out["allOf"] = schemaSliceToBsonM(s.AllOf)
+	}
+	if s.Not != nil {
+		out["not"] = s.Not.ToBsonM()
+	}
+	return out
+}
+
+// Checks if the schema has no keywords, so it accepts any value
+func (s *Schema) IsEmpty() bool {
+	return len(s.ToBsonM()) == 0
+}
+
+// Copies the schema and its nested schemas, so the copy can be changed without changing the schema
+func (s *Schema) Copy() *Schema {
+	if s == nil {
+		return nil
+	}
+
+	out := *s
+	out.BsonType = copyStrings(s.BsonType)
+	out.Required = copyStrings(s.Required)
+	if s.Enum != nil {
+		out.Enum = copyValue(s.Enum).([]interface{})
+	}
+	if s.Extra != nil {
+		out.Extra = copyValue(s.Extra).(map[string]interface{})
+	}
+	out.Properties = copySchemaMap(s.Properties)
+	out.PatternProperties = copySchemaMap(s.PatternProperties)
+	out.AdditionalProperties = s.AdditionalProperties.copy()
+	out.Items = s.Items.Copy()
+	out.TupleItems = copySchemaSlice(s.TupleItems)
+	out.AdditionalItems = s.AdditionalItems.copy()
+	out.OneOf = copySchemaSlice(s.OneOf)
+	out.AnyOf = copySchemaSlice(s.AnyOf)
+	out.AllOf = copySchemaSlice(s.AllOf)
+	out.Not = s.Not.Copy()
+	return &out
+}
+
+// Encodes the schema as the json object of ToBsonM
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToBsonM())
+}
+
+// Decodes a json object with ParseJSON
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	parsed, err := ParseJSON(data)
+	if err != nil {
+		return err
+	}
+	*s = *parsed
+	return nil
+}
+
+func (b *BoolOrSchema) toValue() interface{} {
+	if b.Schema != nil {
+		return b.Schema.ToBsonM()
+	}
+	return b.Allow
+}
+
+func (b *BoolOrSchema) copy() *BoolOrSchema {
+	if b == nil {
+		return nil
+	}
+	return &BoolOrSchema{Allow: b.Allow, Schema: b.Schema.Copy()}
+}
+
+func schemaMapToBsonM(items map[string]*Schema) map[string]interface{} {
+	out := map[string]interface{}{}
+	for k, v := range items {
+		out[k] = v.ToBsonM()
+	}
+	return out
+}
+
+func schemaSliceToBsonM(items []*Schema) []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		out = append(out, item.ToBsonM())
+	}
+	return out
+}
+
+func copySchemaMap(items map[string]*Schema) map[string]*Schema {
+	if items == nil {
+		return nil
+	}
+	out := make(map[string]*Schema, len(items))
+	for k, v := range items {
+		out[k] = v.Copy()
+	}
+	return out
+}
+
+func copySchemaSlice(items []*Schema) []*Schema {
+	if items == nil {
+		return nil
+	}
+	out := make([]*Schema, 0, len(items))
+	for _, item := range items {
+		out = append(out, item.Copy())
+	}
+	return out
+}
+
+func copyStrings(items []string) []string {
+	if items == nil {
+		return nil
+	}
+	return append([]string{}, items...)
+}
+
+// Copies maps and lists of values, so the values of Extra and Enum are not shared
+func copyValue(val interface{}) interface{} {
+	switch item := val.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(item))
+		for k, v := range item {
+			out[k] = copyValue(v)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, 0, len(item))
+		for _, v := range item {
+			out = append(out, copyValue(v))
+		}
+		return out
+	case []map[string]interface{}:
+		out := make([]map[string]interface{}, 0, len(item))
+		for _, v := range item {
+			out = append(out, copyValue(v).(map[string]interface{}))
+		}
+		return out
+	case []string:
+		return append([]string{}, item...)
+	}
+	return val
+}
@@ -0,0 +1,74 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type toBsonMTest struct {
+	arg  *Schema
+	want bsonM
+}
+
+func TestToBsonM(t *testing.T) {
+	tests := []toBsonMTest{
+		{nil, bsonM{}},
+		{&Schema{}, bsonM{}},
+		{&Schema{BsonType: []string{"object"}, Required: []string{}, AdditionalProperties: &BoolOrSchema{Allow: false}},
+			bsonM{"bsonType": []string{"object"}, "required": []string{}, "additionalProperties": false}},
+		{&Schema{Items: &Schema{MinLength: CreateVal(1)}, UniqueItems: CreateVal(false)},
+			bsonM{"items": bsonM{"minLength": 1}, "uniqueItems": false}},
+		{&Schema{TupleItems: []*Schema{{}}, AdditionalItems: &BoolOrSchema{Allow: true, Schema: &Schema{Pattern: CreateVal("^a")}}},
+			bsonM{"items": []bsonM{{}}, "additionalItems": bsonM{"pattern": "^a"}}},
+		{&Schema{Minimum: CreateVal(0.0), ExclusiveMinimum: true, Not: &Schema{Enum: []interface{}{0.0}}},
+			bsonM{"minimum": 0.0, "exclusiveMinimum": true, "not": bsonM{"enum": []interface{}{0.0}}}},
+		{&Schema{AnyOf: []*Schema{}, Extra: bsonM{"$comment": "kept"}},
+			bsonM{"anyOf": []bsonM{}, "$comment": "kept"}},
+	}
+
+	for _, test := range tests {
+		if have := test.arg.ToBsonM(); !reflect.DeepEqual(have, test.want) {
+			t.Errorf("\nGot: %#v;\nWant: %#v", have, test.want)
+		}
+	}
+}
+
+func TestCopy(t *testing.T) {
+	schema := &Schema{
+		BsonType:   []string{"object"},
+		Enum:       []interface{}{bsonM{"a": 1}},
+		Properties: map[string]*Schema{"a": {BsonType: []string{"int"}}},
+		OneOf:      []*Schema{{Required: []string{"a"}}},
+	}
+	want := schema.ToBsonM()
+
+	have := schema.Copy()
+	have.BsonType[0] = "array"
+	have.Enum[0].(bsonM)["a"] = 2
+	have.Properties["a"].BsonType = nil
+	have.OneOf[0].Required[0] = "b"
+
+	if !reflect.DeepEqual(schema.ToBsonM(), want) {
+		t.Errorf("\nGot: %#v;\nWant: %#v", schema.ToBsonM(), want)
+	}
+}
+
+func TestSchemaJSON(t *testing.T) {
+	schema := &Schema{
+		BsonType:   []string{"object"},
+		Required:   []string{},
+		Properties: map[string]*Schema{"a": {BsonType: []string{"int"}, Maximum: CreateVal(10.0), Enum: []interface{}{1, 2}}},
+	}
+	want := `{"bsonType":["object"],"properties":{"a":{"bsonType":["int"],"enum":[1,2],"maximum":10}},"required":[]}`
+
+	data, err := json.Marshal(schema)
+	if err != nil || string(data) != want {
+		t.Errorf("\nGot: %#v;\nWant: %#v;\nError: %v", string(data), want, err)
+	}
+
+	have := &Schema{}
+	if err := json.Unmarshal(data, have); err != nil || !reflect.DeepEqual(have, schema) {
+		t.Errorf("\nGot: %#v;\nWant: %#v;\nError: %v", have, schema, err)
+	}
+}
@@ -7,33 +7,113 @@ import (
 	"github.com/s-augustovitko/mongo-schema-go/internal/validation"
 )
 
+// Returned when the schema is not created from a struct type
+type NotStructError struct {
+	Type reflect.Type
+}
+
+func (e NotStructError) Error() string {
+	return fmt.Sprintf("to create a validation you must send a struct, got [%v]", e.Type)
+}
+
+// Returned when the paths of WithOverride or WithFieldTags are not fields of the struct
+// the paths of LoadOverrides are returned as warnings instead
+type UnknownPathError struct {
+	Paths []string
+}
+
+func (e UnknownPathError) Error() string {
+	return fmt.Sprintf("the override paths %v are not fields of the struct", e.Paths)
+}
+
 // Builds the jsonSchema from a struct
-// Struct can be empty except for arrays, all arrays must be filled with at least 1 item of its kind
+// Only the type of schema is used, so it can be an empty struct or a nil pointer to a struct
 // Returns: Schema, Warnings (ErrorWithTag), Error
 // Any warnings are fields that could not be processed, so they will not show up in the final schema
 func Marshal(schema interface{}, title string, additionalProps bool) (out validation.BsonM, warnings []error, err error) {
-	if title == "" {
-		title = "Schema Validation"
+	return MarshalWith(schema, Title(title), AdditionalProperties(additionalProps))
+}
+
+// Builds the jsonSchema from a struct with the options, eg.
+// MarshalWith(obj, Title("Users"), Strict(), NamingPolicy(NamingLowerCase), ValidationLevel(LevelModerate))
+// Only the type of schema is used, so it can be an empty struct or a nil pointer to a struct
+func MarshalWith(schema interface{}, opts ...Option) (out validation.BsonM, warnings []error, err error) {
+	return MarshalType(reflect.TypeOf(schema), opts...)
+}
+
+// Builds the jsonSchema from the type T, same as Marshal but no value of T is needed
+func For[T any](opts ...Option) (out validation.BsonM, warnings []error, err error) {
+	return MarshalType(reflect.TypeOf((*T)(nil)).Elem(), opts...)
+}
+
+// Builds the jsonSchema from a struct type or a pointer to a struct type
+// Returns a NotStructError if typ is not a struct
+func MarshalType(typ reflect.Type, opts ...Option) (out validation.BsonM, warnings []error, err error) {
+	options := newOptions(opts...)
+	jsonSchema, warnings, err := buildType(typ, options)
+	if _, ok := err.(NotStructError); ok {
+		// Only the root is returned, since there is no struct to validate
+		return rootSchema(jsonSchema), warnings, err
+	}
+	return validator(jsonSchema, options), warnings, err
+}
+
+// Builds the typed root object of a struct type, the root has the title and additionalProperties of the options
+// the root is returned even with an error, so the validator has the same shape
+func buildType(typ reflect.Type, options Options) (*Schema, []error, error) {
+	jsonSchema := &Schema{
+		BsonType:             []string{"object"},
+		Title:                CreateVal(options.title()),
+		AdditionalProperties: &BoolOrSchema{Allow: options.additionalProperties()},
+	}
+
+	structTyp := typ
+	for structTyp != nil && structTyp.Kind() == reflect.Pointer {
+		structTyp = structTyp.Elem()
+	}
+	if structTyp == nil || structTyp.Kind() != reflect.Struct {
+		return jsonSchema, []error{}, NotStructError{Type: typ}
 	}
 
-	jsonSchema := validation.BsonM{
-		"bsonType":             "object",
-		"title":                title,
-		"additionalProperties": additionalProps,
+	validationOpts, ruleWarnings, err := options.validationOptions()
+	if err != nil {
+		return jsonSchema, []error{}, err
+	}
+	errs := append(ruleWarnings, validation.CreateJSONSchema(structTyp, validationOpts, jsonSchema)...)
+	// The title of the options takes precedence over the title tag of the struct
+	if options.Title.Exists {
+		jsonSchema.Title = options.Title
 	}
 
-	value := reflect.ValueOf(schema)
-	if value.Kind() == reflect.Pointer {
-		value = value.Elem()
+	unknown := []string{}
+	for _, path := range validationOpts.Overrides.Unknown() {
+		_, isOverride := options.Overrides[path]
+		_, isFieldTags := options.FieldTags[path]
+		if !isOverride && !isFieldTags {
+			// Paths only used by the rules of LoadOverrides are warnings, since the document is tuned outside of the code
+			errs = append(errs, validation.CreateErrorWithPath(path, fmt.Errorf("the override path is not a field of the struct")))
+			continue
+		}
+		unknown = append(unknown, path)
 	}
-	if value.Kind() != reflect.Struct {
-		return jsonSchema, []error{}, fmt.Errorf("to create a validation you must send a struct")
+	if len(unknown) > 0 {
+		return jsonSchema, errs, UnknownPathError{Paths: unknown}
 	}
+	return jsonSchema, errs, nil
+}
 
-	props := validation.BsonM{}
-	reqs, errs := validation.CreateJSONSchema(value, &props)
-	jsonSchema["properties"] = props
-	jsonSchema["required"] = reqs
+// Gets the validator of the root object
+func validator(jsonSchema *Schema, options Options) validation.BsonM {
+	out := validation.BsonM{"validator": validation.BsonM{"$jsonSchema": rootSchema(jsonSchema)}}
+	if options.ValidationLevel != "" {
+		out["validationLevel"] = string(options.ValidationLevel)
+	}
+	return out
+}
 
-	return validation.BsonM{"validator": validation.BsonM{"$jsonSchema": jsonSchema}}, errs, nil
+// Gets the map of the root object, the bsonType of the root is always written as "object"
+func rootSchema(jsonSchema *Schema) validation.BsonM {
+	out := jsonSchema.ToBsonM()
+	out["bsonType"] = "object"
+	return out
 }
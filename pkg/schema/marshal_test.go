@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 	"time"
@@ -62,32 +63,32 @@ func TestMarshal(t *testing.T) {
 		"additionalProperties": true,
 		"bsonType":             "object",
 		"properties": validation.BsonM{
-			"_id":      validation.BsonM{"bsonType": []string{"objectId"}},
+			"_id":      validation.BsonM{},
 			"assignee": validation.BsonM{"bsonType": []string{"string"}},
 			"attachments": validation.BsonM{"bsonType": []string{"array"},
 				"items": validation.BsonM{
 					"bsonType": []string{"object"},
 					"properties": validation.BsonM{
-						"_id":       validation.BsonM{"bsonType": []string{"objectId"}},
+						"_id":       validation.BsonM{},
 						"createdAt": validation.BsonM{"bsonType": []string{"date"}},
 						"createdBy": validation.BsonM{"bsonType": []string{"string"}},
 						"title":     validation.BsonM{"bsonType": []string{"string"}},
-						"type":      validation.BsonM{"bsonType": []string{"string"}, "enum": []string{"document", "image", "other"}},
+						"type":      validation.BsonM{"bsonType": []string{"string"}, "enum": []interface{}{"document", "image", "other"}},
 						"updatedAt": validation.BsonM{"bsonType": []string{"date"}},
 						"updatedBy": validation.BsonM{"bsonType": []string{"string"}},
 						"url":       validation.BsonM{"bsonType": []string{"string"}, "pattern": "^(ftp|http|https)://[^ \"]+$"}},
 					"required": []string{"url", "createdAt", "createdBy"}},
 				"uniqueItems": false},
-			"boardId":   validation.BsonM{"bsonType": []string{"objectId"}},
+			"boardId":   validation.BsonM{},
 			"content":   validation.BsonM{"bsonType": []string{"string"}},
 			"createdAt": validation.BsonM{"bsonType": []string{"date"}},
 			"createdBy": validation.BsonM{"bsonType": []string{"string"}},
-			"order":     validation.BsonM{"bsonType": []string{"int"}, "minimum": float64(1)},
+			"order":     validation.BsonM{"bsonType": []string{"int", "long"}, "minimum": float64(1)},
 			"reporter":  validation.BsonM{"bsonType": []string{"string"}},
-			"statusId":  validation.BsonM{"bsonType": []string{"objectId"}},
+			"statusId":  validation.BsonM{},
 			"tag": validation.BsonM{"bsonType": []string{"object"},
-				"properties": validation.BsonM{"_id": validation.BsonM{"bsonType": []string{"objectId"}},
-					"boardId":   validation.BsonM{"bsonType": []string{"objectId"}},
+				"properties": validation.BsonM{"_id": validation.BsonM{},
+					"boardId":   validation.BsonM{},
 					"color":     validation.BsonM{"bsonType": []string{"string"}},
 					"createdAt": validation.BsonM{"bsonType": []string{"date"}},
 					"createdBy": validation.BsonM{"bsonType": []string{"string"}},
@@ -97,7 +98,7 @@ func TestMarshal(t *testing.T) {
 				"required": []string{"boardId", "name", "color", "createdAt", "createdBy"}},
 			"tagIDs": validation.BsonM{
 				"bsonType":    []string{"array"},
-				"items":       validation.BsonM{"bsonType": []string{"objectId"}},
+				"items":       validation.BsonM{},
 				"uniqueItems": false},
 			"test": validation.BsonM{
 				"bsonType": []string{"array"},
@@ -110,11 +111,18 @@ func TestMarshal(t *testing.T) {
 		"title":    "Schema Test"},
 	}}
 
-	data := marshalTest{Attachments: []testAttachment{{}}, Test: []string{""}, TagIDs: []interface{}{""}}
-	have, warnings, err := Marshal(&data, "Schema Test", true)
+	args := []interface{}{
+		marshalTest{},
+		&marshalTest{Attachments: []testAttachment{{}}, Test: []string{""}, TagIDs: []interface{}{""}},
+		(*marshalTest)(nil),
+	}
+
+	for _, arg := range args {
+		have, warnings, err := Marshal(arg, "Schema Test", true)
 
-	if err != nil || len(warnings) > 0 || !reflect.DeepEqual(want, have) {
-		t.Errorf("\nGot: %#v;\nWant: %#v;\nWarns: %#v;\nErr: %#v;", have, want, warnings, err)
+		if err != nil || len(warnings) > 0 || !reflect.DeepEqual(want, have) {
+			t.Errorf("\nGot: %#v;\nWant: %#v;\nWarns: %#v;\nErr: %#v;", have, want, warnings, err)
+		}
 	}
 }
 
@@ -125,10 +133,330 @@ func TestMarshalErr(t *testing.T) {
 		"additionalProperties": false,
 	}
 
-	data := ""
-	have, warnings, err := Marshal(data, "", false)
+	args := []interface{}{"", nil, []marshalTest{}}
+
+	for _, arg := range args {
+		have, warnings, err := Marshal(arg, "", false)
+
+		if err == nil || len(warnings) > 0 || !reflect.DeepEqual(want, have) {
+			t.Errorf("\nGot: %#v;\nWant: %#v;\nWarns: %#v;\nErr: %#v;", have, want, warnings, err)
+		}
+	}
+}
+
+func TestFor(t *testing.T) {
+	want, _, _ := Marshal(marshalTest{}, "Schema Test", false)
+
+	haves := []func() (validation.BsonM, []error, error){
+		func() (validation.BsonM, []error, error) {
+			return For[marshalTest](Title("Schema Test"), AdditionalProperties(false))
+		},
+		func() (validation.BsonM, []error, error) {
+			return For[*marshalTest](Title("Schema Test"), AdditionalProperties(false))
+		},
+		func() (validation.BsonM, []error, error) {
+			return MarshalType(reflect.TypeOf(marshalTest{}), Title("Schema Test"), AdditionalProperties(false))
+		},
+	}
+
+	for _, fn := range haves {
+		have, warnings, err := fn()
+
+		if err != nil || len(warnings) > 0 || !reflect.DeepEqual(want, have) {
+			t.Errorf("\nGot: %#v;\nWant: %#v;\nWarns: %#v;\nErr: %#v;", have, want, warnings, err)
+		}
+	}
+}
+
+func TestMarshalTypeErr(t *testing.T) {
+	args := []reflect.Type{nil, reflect.TypeOf(""), reflect.TypeOf([]marshalTest{}), reflect.TypeOf((**int)(nil))}
+
+	for _, arg := range args {
+		_, _, err := MarshalType(arg)
+
+		var notStruct NotStructError
+		if !errors.As(err, &notStruct) || notStruct.Type != arg {
+			t.Errorf("\nArg: %v;\nErr: %#v;", arg, err)
+		}
+	}
+
+	if _, _, err := For[int](); err == nil {
+		t.Errorf("\nFor[int] should fail;\nErr: %#v;", err)
+	}
+}
+
+type marshalStrictItem struct {
+	Name string `bson:"name"`
+}
+
+type marshalStrictTest struct {
+	_     struct{}            `title:"Strict Test"`
+	Item  marshalStrictItem   `bson:"item"`
+	Items []marshalStrictItem `bson:"items"`
+	Open  marshalStrictItem   `bson:"open" additionalProperties:"true"`
+}
+
+type marshalStrictCase struct {
+	arg  []Option
+	want validation.BsonM
+}
+
+func TestMarshalStrict(t *testing.T) {
+	strict := validation.BsonM{
+		"bsonType": "object", "title": "Strict Test", "additionalProperties": false, "required": []string{},
+		"properties": validation.BsonM{
+			"_id": validation.BsonM{"bsonType": []string{"objectId"}},
+			"item": validation.BsonM{
+				"bsonType":             []string{"object"},
+				"additionalProperties": false,
+				"properties":           validation.BsonM{"name": validation.BsonM{"bsonType": []string{"string"}}},
+				"required":             []string{}},
+			"items": validation.BsonM{"bsonType": []string{"array"}, "uniqueItems": false, "items": validation.BsonM{
+				"bsonType":             []string{"object"},
+				"additionalProperties": false,
+				"properties":           validation.BsonM{"name": validation.BsonM{"bsonType": []string{"string"}}},
+				"required":             []string{}}},
+			"open": validation.BsonM{
+				"bsonType":             []string{"object"},
+				"additionalProperties": true,
+				"properties":           validation.BsonM{"name": validation.BsonM{"bsonType": []string{"string"}}},
+				"required":             []string{}}}}
+
+	tests := []marshalStrictCase{
+		{[]Option{}, validation.BsonM{
+			"bsonType": "object", "title": "Strict Test", "additionalProperties": true, "required": []string{},
+			"properties": validation.BsonM{
+				"item": validation.BsonM{
+					"bsonType":   []string{"object"},
+					"properties": validation.BsonM{"name": validation.BsonM{"bsonType": []string{"string"}}},
+					"required":   []string{}},
+				"items": validation.BsonM{"bsonType": []string{"array"}, "uniqueItems": false, "items": validation.BsonM{
+					"bsonType":   []string{"object"},
+					"properties": validation.BsonM{"name": validation.BsonM{"bsonType": []string{"string"}}},
+					"required":   []string{}}},
+				"open": validation.BsonM{
+					"bsonType":             []string{"object"},
+					"additionalProperties": true,
+					"properties":           validation.BsonM{"name": validation.BsonM{"bsonType": []string{"string"}}},
+					"required":             []string{}}}}},
+		{[]Option{AdditionalProperties(false)}, validation.BsonM{
+			"bsonType": "object", "title": "Strict Test", "additionalProperties": false, "required": []string{},
+			"properties": validation.BsonM{
+				"_id": validation.BsonM{"bsonType": []string{"objectId"}},
+				"item": validation.BsonM{
+					"bsonType":   []string{"object"},
+					"properties": validation.BsonM{"name": validation.BsonM{"bsonType": []string{"string"}}},
+					"required":   []string{}},
+				"items": validation.BsonM{"bsonType": []string{"array"}, "uniqueItems": false, "items": validation.BsonM{
+					"bsonType":   []string{"object"},
+					"properties": validation.BsonM{"name": validation.BsonM{"bsonType": []string{"string"}}},
+					"required":   []string{}}},
+				"open": validation.BsonM{
+					"bsonType":             []string{"object"},
+					"additionalProperties": true,
+					"properties":           validation.BsonM{"name": validation.BsonM{"bsonType": []string{"string"}}},
+					"required":             []string{}}}}},
+		{[]Option{AdditionalProperties(false), InheritAdditionalProperties()}, strict},
+		{[]Option{Strict()}, strict},
+	}
+
+	for _, test := range tests {
+		out, warnings, err := For[marshalStrictTest](test.arg...)
+		have := out["validator"].(validation.BsonM)["$jsonSchema"]
+
+		if err != nil || len(warnings) > 0 || !reflect.DeepEqual(have, test.want) {
+			t.Errorf("\nGot: %#v;\nWant: %#v;\nWarns: %#v;\nErr: %#v;", have, test.want, warnings, err)
+		}
+	}
+}
+
+type marshalOverrideAddress struct {
+	Street string `bson:"street"`
+	Zip    string `bson:"zip"`
+}
+
+type marshalOverrideTest struct {
+	Address marshalOverrideAddress `bson:"address"`
+	Billing marshalOverrideAddress `bson:"billing"`
+}
+
+func TestMarshalOverrides(t *testing.T) {
+	opts := []Option{
+		WithFieldTags("address.zip", `validation:"min=1"`),
+		WithFieldTags("address.zip", `validation:"required,pattern=^\\d{5}$"`),
+		WithOverride("billing", map[string]interface{}{"bsonType": "string"}),
+	}
+	want := validation.BsonM{
+		"bsonType": "object", "title": "Schema Validation", "additionalProperties": true, "required": []string{},
+		"properties": validation.BsonM{
+			"address": validation.BsonM{
+				"bsonType": []string{"object"},
+				"required": []string{"zip"},
+				"properties": validation.BsonM{
+					"street": validation.BsonM{"bsonType": []string{"string"}},
+					"zip":    validation.BsonM{"bsonType": []string{"string"}, "minLength": 1, "pattern": `^\d{5}$`}}},
+			"billing": validation.BsonM{"bsonType": []string{"string"}}}}
+
+	out, warnings, err := For[marshalOverrideTest](opts...)
+	have := out["validator"].(validation.BsonM)["$jsonSchema"]
+	if err != nil || len(warnings) > 0 || !reflect.DeepEqual(have, want) {
+		t.Errorf("\nGot: %#v;\nWant: %#v;\nWarnings: %v;\nError: %v", have, want, warnings, err)
+	}
+
+	// The overrides of the options are not shared between calls
+	if _, _, err := For[marshalOverrideTest](opts...); err != nil {
+		t.Errorf("\nGot: %#v;\nWant: nil", err)
+	}
+
+	_, _, err = For[marshalOverrideTest](WithOverride("billing.country", nil), WithFieldTags("address.unknown", `validation:"required"`))
+	wantErr := UnknownPathError{Paths: []string{"address.unknown", "billing.country"}}
+	if !reflect.DeepEqual(err, wantErr) {
+		t.Errorf("\nGot: %#v;\nWant: %#v", err, wantErr)
+	}
+
+	_, _, err = For[marshalOverrideTest](WithOverride("billing", map[string]interface{}{"bsonType": 1}))
+	if want := "invalid override of [billing], [bsonType]: expected a list, got [1]"; err == nil || err.Error() != want {
+		t.Errorf("\nGot: %#v;\nWant: %#v", err, want)
+	}
+
+	_, _, err = For[marshalOverrideTest](WithFieldTags("address.zip", `validation:"bogus=1"`))
+	if want := "invalid override of [address.zip], invalid [validation] tag at offset 0: invalid validation [bogus]"; err == nil || err.Error() != want {
+		t.Errorf("\nGot: %#v;\nWant: %#v", err, want)
+	}
+}
+
+type marshalFieldTagsTest struct {
+	Age  int    `bson:"age" validation:"required,min=1,max=120"`
+	Name string `bson:"name" validation:"max=64" description:"Name"`
+}
+
+// The validations of WithFieldTags are added to the ones of the field, like the rules of LoadOverrides
+func TestMarshalFieldTags(t *testing.T) {
+	want := validation.BsonM{
+		"age":  validation.BsonM{"bsonType": []string{"int", "long"}, "minimum": float64(1), "maximum": float64(5)},
+		"name": validation.BsonM{"bsonType": []string{"string"}, "description": "Full name", "minLength": 1, "maxLength": 64},
+	}
+
+	out, warnings, err := For[marshalFieldTagsTest](
+		WithFieldTags("age", `validation:"max=5"`),
+		WithFieldTags("name", `validation:"min=1" description:"Full name"`),
+	)
+	jsonSchema := out["validator"].(validation.BsonM)["$jsonSchema"].(validation.BsonM)
+	if err != nil || len(warnings) > 0 || !reflect.DeepEqual(jsonSchema["properties"], want) {
+		t.Errorf("\nGot: %#v;\nWant: %#v;\nWarnings: %v;\nError: %v", jsonSchema["properties"], want, warnings, err)
+	}
+	if required := []string{"age"}; !reflect.DeepEqual(jsonSchema["required"], required) {
+		t.Errorf("\nGot: %#v;\nWant: %#v", jsonSchema["required"], required)
+	}
+}
+
+type marshalWithItem struct {
+	ItemName string
+}
+
+type marshalWithTest struct {
+	UserID    interface{}
+	CreatedAt string `type:"date"`
+	Item      marshalWithItem
+	Named     string `bson:"Named"`
+}
+
+func TestMarshalWith(t *testing.T) {
+	want := validation.BsonM{
+		"validationLevel": "moderate",
+		"validator": validation.BsonM{"$jsonSchema": validation.BsonM{
+			"bsonType": "object", "title": "With", "additionalProperties": false, "required": []string{},
+			"properties": validation.BsonM{
+				"_id":        validation.BsonM{"bsonType": []string{"objectId"}},
+				"user_id":    validation.BsonM{},
+				"created_at": validation.BsonM{"bsonType": []string{"date"}},
+				"item": validation.BsonM{
+					"bsonType":             []string{"object"},
+					"additionalProperties": false,
+					"properties":           validation.BsonM{"item_name": validation.BsonM{"bsonType": []string{"string"}}},
+					"required":             []string{}},
+				"Named": validation.BsonM{"bsonType": []string{"string"}}}}},
+	}
+
+	have, warnings, err := MarshalWith(marshalWithTest{}, Title("With"), Strict(), NamingPolicy(NamingSnakeCase), ValidationLevel(LevelModerate))
+	if err != nil || len(warnings) > 0 || !reflect.DeepEqual(have, want) {
+		t.Errorf("\nGot: %#v;\nWant: %#v;\nWarnings: %v;\nError: %v", have, want, warnings, err)
+	}
+
+	// Marshal is MarshalWith with the title and additional properties
+	marshal, _, _ := Marshal(marshalWithTest{}, "With", false)
+	with, _, _ := MarshalWith(marshalWithTest{}, Title("With"), AdditionalProperties(false))
+	if !reflect.DeepEqual(marshal, with) {
+		t.Errorf("\nGot: %#v;\nWant: %#v", marshal, with)
+	}
+
+	_, _, err = MarshalWith(1, Strict())
+	if !errors.As(err, &NotStructError{}) {
+		t.Errorf("\nGot: %#v;\nWant: %#v", err, NotStructError{Type: reflect.TypeOf(1)})
+	}
+}
+
+type marshalTitleTest struct {
+	_    struct{} `title:"Tagged"`
+	Name string
+}
+
+type marshalTitleTestCase struct {
+	arg  []Option
+	want string
+}
+
+func TestMarshalTitle(t *testing.T) {
+	tests := []marshalTitleTestCase{
+		{nil, "Tagged"},
+		{[]Option{Title("")}, "Tagged"},
+		{[]Option{Title("Explicit")}, "Explicit"},
+		{[]Option{Title("Explicit"), Strict()}, "Explicit"},
+		// Custom options that set the fields are the same as the built-in ones
+		{[]Option{func(o *Options) { o.Title = CreateVal("Custom") }}, "Custom"},
+		{[]Option{func(o *Options) { o.Title = CreateVal("") }}, "Tagged"},
+	}
+
+	for _, test := range tests {
+		out, _, err := For[marshalTitleTest](test.arg...)
+		have := out["validator"].(validation.BsonM)["$jsonSchema"].(validation.BsonM)["title"]
+		if err != nil || have != test.want {
+			t.Errorf("\nGot: %#v;\nWant: %#v;\nError: %v", have, test.want, err)
+		}
+	}
+
+	if out, _, _ := Marshal(marshalTitleTest{}, "Explicit", true); out["validator"].(validation.BsonM)["$jsonSchema"].(validation.BsonM)["title"] != "Explicit" {
+		t.Errorf("\nGot: %#v;\nWant: %#v", out, "Explicit")
+	}
+
+	custom := func(o *Options) {
+		o.Title = CreateVal("Users")
+		o.AdditionalProperties = CreateVal(false)
+	}
+	want, _ := Object().Title("Accounts").AdditionalProperties(true).Marshal(Title("Users"), AdditionalProperties(false))
+	if have, err := Object().Title("Accounts").AdditionalProperties(true).Marshal(custom); err != nil || !reflect.DeepEqual(have, want) {
+		t.Errorf("\nGot: %#v;\nWant: %#v;\nError: %v", have, want, err)
+	}
+}
+
+type marshalWarningsTestAddress struct {
+	ZipCode string `validation:"bogus=1"`
+}
+
+type marshalWarningsTest struct {
+	HomeAddress marshalWarningsTestAddress
+}
+
+// Warnings name the fields by their path in the schema
+func TestMarshalWarnings(t *testing.T) {
+	_, warnings, err := For[marshalWarningsTest](NamingPolicy(NamingSnakeCase))
+	want := "[home_address.zip_code]: invalid [validation] tag at offset 0: invalid validation [bogus]"
+	if err != nil || len(warnings) != 1 || warnings[0].Error() != want {
+		t.Fatalf("\nGot: %v;\nWant: %#v;\nError: %v", warnings, want, err)
+	}
 
-	if err == nil || len(warnings) > 0 || !reflect.DeepEqual(want, have) {
-		t.Errorf("\nGot: %#v;\nWant: %#v;\nWarns: %#v;\nErr: %#v;", have, want, warnings, err)
+	warning := warnings[0].(validation.ErrorWithTag)
+	if warning.Tag() != "zip_code" || warning.Path() != "home_address.zip_code" || warning.Name() != "ZipCode" {
+		t.Errorf("\nGot: %#v, %#v, %#v;\nWant: %#v, %#v, %#v", warning.Tag(), warning.Path(), warning.Name(), "zip_code", "home_address.zip_code", "ZipCode")
 	}
 }
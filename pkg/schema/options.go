@@ -0,0 +1,262 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/s-augustovitko/mongo-schema-go/internal/tags"
+	"github.com/s-augustovitko/mongo-schema-go/internal/validation"
+	"github.com/s-augustovitko/mongo-schema-go/pkg/schema/jsonschema"
+)
+
+const defaultTitle = "Schema Validation"
+
+// Policy used to map the go numeric kinds to bson types
+type NumericMode = tags.NumericMode
+
+const (
+	// Numeric fields have the bson types the go driver writes for them (default)
+	NumericStrict = tags.NumericStrict
+	// Integer fields accept "int" and "long", and float fields also accept integers
+	NumericPermissive = tags.NumericPermissive
+)
+
+// Policy used to name the fields that do not have a name in their field or bson tags
+type NamingMode = tags.NamingMode
+
+const (
+	// The first character is lower cased, eg. "UserID" is "userID" (default)
+	NamingLowerFirst = tags.NamingLowerFirst
+	// Every character is lower cased like the go driver does, eg. "UserID" is "userid"
+	NamingLowerCase = tags.NamingLowerCase
+	// Words are lower cased and separated by underscores, eg. "UserID" is "user_id"
+	NamingSnakeCase = tags.NamingSnakeCase
+	// The name of the go field, eg. "UserID" is "UserID"
+	NamingAsIs = tags.NamingAsIs
+)
+
+// Level of the validation of the collection, see the validationLevel option of createCollection and collMod
+type Level string
+
+const (
+	// Documents are not validated
+	LevelOff Level = "off"
+	// Inserts and updates are validated (default of MongoDB)
+	LevelStrict Level = "strict"
+	// Inserts and updates of documents that are already valid are validated
+	LevelModerate Level = "moderate"
+)
+
+// Options used to build the jsonSchema
+type Options struct {
+	// Title of the root object, when it is not set the title tag of the struct or "Schema Validation" is used
+	Title jsonschema.Optional[string]
+	// If the root object allows properties that are not in the struct, true when it is not set
+	AdditionalProperties jsonschema.Optional[bool]
+	NumericPolicy        NumericMode
+	NamingPolicy         NamingMode
+	// Written next to the validator when it is set
+	ValidationLevel Level
+	Nullable        bool
+	InferRequired   bool
+	MaxRecursion    int
+	// Nested objects without their own additionalProperties use the one of the root
+	InheritAdditionalProperties bool
+	// Schemas that replace the fields at the dotted paths from the root struct (eg. "address.zip")
+	Overrides map[string]map[string]interface{}
+	// Struct tags merged into the tags of the fields at the dotted paths from the root struct
+	FieldTags map[string]string
+	// Rules of the fields at the dotted paths from the root struct, see LoadOverrides
+	FieldRules map[string]FieldRules
+}
+
+type Option func(*Options)
+
+// Creates the options and applies opts, an empty title is the same as a title that is not set
+func newOptions(opts ...Option) Options {
+	out := Options{}
+	for _, opt := range opts {
+		opt(&out)
+	}
+
+	if out.Title.Val == "" {
+		out.Title = jsonschema.Optional[string]{}
+	}
+	return out
+}
+
+// Gets the title of the root object, "Schema Validation" if it is not set
+func (o Options) title() string {
+	if o.Title.Exists && o.Title.Val != "" {
+		return o.Title.Val
+	}
+	return defaultTitle
+}
+
+// Gets if the root object allows additional properties, true if it is not set
+func (o Options) additionalProperties() bool {
+	return !o.AdditionalProperties.Exists || o.AdditionalProperties.Val
+}
+
+// Gets the options used while walking the struct and the warnings of the rules of LoadOverrides
+// returns an error if a schema of WithOverride or the tags of WithFieldTags are not valid
+func (o Options) validationOptions() (validation.Options, []error, error) {
+	warnings := []error{}
+	out := validation.Options{
+		NumericPolicy: o.NumericPolicy,
+		NamingPolicy:  o.NamingPolicy,
+		Nullable:      o.Nullable,
+		InferRequired: o.InferRequired,
+		MaxRecursion:  o.MaxRecursion,
+	}
+	if o.InheritAdditionalProperties {
+		out.AdditionalProperties = validation.CreateVal(o.additionalProperties())
+	}
+	if len(o.Overrides) > 0 || len(o.FieldTags) > 0 || len(o.FieldRules) > 0 {
+		overrides := map[string]validation.Override{}
+		paths := make([]string, 0, len(o.FieldRules))
+		for path := range o.FieldRules {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			rules := o.FieldRules[path]
+			for _, err := range rules.Errors {
+				warnings = append(warnings, validation.CreateErrorWithPath(path, err))
+			}
+			overrides[path] = rules.override()
+		}
+		for path, fragment := range o.Overrides {
+			schema, err := jsonschema.Parse(fragment)
+			if err != nil {
+				return out, warnings, fmt.Errorf("invalid override of [%v], %v", path, err)
+			}
+			item := overrides[path]
+			item.Schema = schema
+			overrides[path] = item
+		}
+		// The tags of the rules take precedence, so they can be tuned without changing the code
+		for path, tags := range o.FieldTags {
+			if err := (validation.Override{Tags: tags}).Check(); err != nil {
+				return out, warnings, fmt.Errorf("invalid override of [%v], %v", path, err)
+			}
+			item := overrides[path]
+			item.Tags = strings.TrimSpace(item.Tags + " " + tags)
+			overrides[path] = item
+		}
+		out.Overrides = validation.NewOverrides(overrides)
+	}
+	return out, warnings, nil
+}
+
+// Sets the title of the root object, empty titles fall back to the title tag of the struct or "Schema Validation"
+func Title(title string) Option {
+	return func(o *Options) {
+		o.Title = jsonschema.CreateVal(title)
+	}
+}
+
+// Sets if the root object allows properties that are not in the struct
+// when it does not, _id is always allowed since the driver adds it to the documents without one
+func AdditionalProperties(allow bool) Option {
+	return func(o *Options) {
+		o.AdditionalProperties = jsonschema.CreateVal(allow)
+	}
+}
+
+// Sets the additionalProperties of the root object to every nested object (structs, array items and map values)
+// that does not set it with the additionalProperties tag or an inline map
+func InheritAdditionalProperties() Option {
+	return func(o *Options) {
+		o.InheritAdditionalProperties = true
+	}
+}
+
+// Sets the policy used to map numeric fields to bson types
+func NumericPolicy(mode NumericMode) Option {
+	return func(o *Options) {
+		o.NumericPolicy = mode
+	}
+}
+
+// Sets the policy used to name the fields that do not have a name in their field or bson tags
+// the go driver lower cases the whole name, use NamingLowerCase to match the documents it writes
+func NamingPolicy(mode NamingMode) Option {
+	return func(o *Options) {
+		o.NamingPolicy = mode
+	}
+}
+
+// Sets the validationLevel written next to the validator, so the output can be used as it is in createCollection or collMod
+func ValidationLevel(level Level) Option {
+	return func(o *Options) {
+		o.ValidationLevel = level
+	}
+}
+
+// Does not allow properties that are not in the structs, in the root and in every nested object
+// same as AdditionalProperties(false) and InheritAdditionalProperties()
+func Strict() Option {
+	return func(o *Options) {
+		o.AdditionalProperties = jsonschema.CreateVal(false)
+		o.InheritAdditionalProperties = true
+	}
+}
+
+// Adds the "null" bson type to pointer, slice, map and omitempty fields
+// so documents with nil values written by the go driver pass the validation
+func Nullable() Option {
+	return func(o *Options) {
+		o.Nullable = true
+	}
+}
+
+// Makes every field without omitempty required, since the go driver always writes them
+func InferRequired() Option {
+	return func(o *Options) {
+		o.InferRequired = true
+	}
+}
+
+// Sets the times a recursive type (eg. type Comment struct { Replies []Comment }) is expanded
+// after that its objects have no properties, by default recursive fields are skipped with a warning
+// negative depths are the same as 0
+func MaxRecursion(depth int) Option {
+	return func(o *Options) {
+		if depth < 0 {
+			depth = 0
+		}
+		o.MaxRecursion = depth
+	}
+}
+
+// Replaces the schema of the field at the dotted path from the root struct (eg. "address.zip"), for types that can not be tagged
+// array items and map values do not add to the path, so "addresses.zip" is the zip of the items of addresses
+// paths that are not found return an UnknownPathError
+func WithOverride(path string, fragment map[string]interface{}) Option {
+	return func(o *Options) {
+		if o.Overrides == nil {
+			o.Overrides = map[string]map[string]interface{}{}
+		}
+		o.Overrides[path] = fragment
+	}
+}
+
+// Merges struct tags into the tags of the field at the dotted path from the root struct, eg.
+// WithFieldTags("address.zip", `validation:"pattern=^\d{5}$"`), the keys of the tags replace the ones of the field
+// except validation and items, which are added to the ones of the field like the rules of LoadOverrides
+// paths are found by the names of the fields before the tags are merged, paths that are not found return an UnknownPathError
+func WithFieldTags(path, tags string) Option {
+	return func(o *Options) {
+		if o.FieldTags == nil {
+			o.FieldTags = map[string]string{}
+		}
+		merged, err := validation.MergeTags(o.FieldTags[path], tags)
+		if err != nil {
+			// The first value of a key is used, so the later tags go first, the error is returned when the schema is created
+			merged = strings.TrimSpace(tags + " " + o.FieldTags[path])
+		}
+		o.FieldTags[path] = merged
+	}
+}
@@ -0,0 +1,39 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+type newOptionsTest struct {
+	arg  []Option
+	want Options
+}
+
+func TestNewOptions(t *testing.T) {
+	tests := []newOptionsTest{
+		{nil, Options{}},
+		{[]Option{Title("")}, Options{}},
+		{[]Option{Title("Test"), AdditionalProperties(false)}, Options{Title: CreateVal("Test"), AdditionalProperties: CreateVal(false)}},
+		{[]Option{AdditionalProperties(false), AdditionalProperties(true)}, Options{AdditionalProperties: CreateVal(true)}},
+		{[]Option{NumericPolicy(NumericPermissive)}, Options{NumericPolicy: NumericPermissive}},
+		{[]Option{Nullable()}, Options{Nullable: true}},
+		{[]Option{InferRequired()}, Options{InferRequired: true}},
+		{[]Option{MaxRecursion(3)}, Options{MaxRecursion: 3}},
+		{[]Option{MaxRecursion(-1)}, Options{}},
+		{[]Option{InheritAdditionalProperties()}, Options{InheritAdditionalProperties: true}},
+		{[]Option{NamingPolicy(NamingSnakeCase)}, Options{NamingPolicy: NamingSnakeCase}},
+		{[]Option{ValidationLevel(LevelModerate)}, Options{ValidationLevel: LevelModerate}},
+		{[]Option{Strict()}, Options{AdditionalProperties: CreateVal(false), InheritAdditionalProperties: true}},
+		{[]Option{WithOverride("a.b", map[string]interface{}{"bsonType": "string"})}, Options{
+			Overrides: map[string]map[string]interface{}{"a.b": {"bsonType": "string"}}}},
+		{[]Option{WithFieldTags("a", `enum:"x"`), WithFieldTags("a", `validation:"required"`)}, Options{
+			FieldTags: map[string]string{"a": `validation:"required" enum:"x"`}}},
+	}
+
+	for _, test := range tests {
+		if have := newOptions(test.arg...); !reflect.DeepEqual(have, test.want) {
+			t.Errorf("\nGot: %#v;\nWant: %#v", have, test.want)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/s-augustovitko/mongo-schema-go/internal/validation"
+)
+
+// Rules of a field loaded with LoadOverrides, the values use the syntax of the tags
+// Validation and Items are added to the validation and items tags of the field, the other rules replace the tags
+type FieldRules struct {
+	Validation  string
+	Items       string
+	Enum        string
+	Description string
+	Type        string
+	ItemsType   string
+	// Keywords of the document that could not be read, they are reported as warnings of the path when the schema is created
+	Errors []error
+}
+
+// Keywords of the override documents, by the tag they set
+var ruleKeywords = map[string]func(rules *FieldRules, val string){
+	"validation":  func(rules *FieldRules, val string) { rules.Validation = val },
+	"items":       func(rules *FieldRules, val string) { rules.Items = val },
+	"enum":        func(rules *FieldRules, val string) { rules.Enum = val },
+	"description": func(rules *FieldRules, val string) { rules.Description = val },
+	"type":        func(rules *FieldRules, val string) { rules.Type = val },
+	"itemsType":   func(rules *FieldRules, val string) { rules.ItemsType = val },
+}
+
+// Reads a json document with the rules of the fields by their dotted path from the root struct, eg.
+// {"address.zip": {"validation": "required,pattern=^\\d{5}$", "description": "Zip code"}, "status": {"enum": ["active", "closed"]}}
+// enum, type and itemsType can also be lists, strings of the enum list are always strings
+// Returns an error if the document is not a json object, invalid rules are skipped and, like the paths that
+// are not fields of the struct, returned as warnings (ErrorWithTag) with their path when the schema is created
+func LoadOverrides(r io.Reader) (Option, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	var doc map[string]interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid overrides document, %v", err)
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("invalid overrides document, unexpected data after the object")
+	}
+
+	paths := make([]string, 0, len(doc))
+	for path := range doc {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	rules := map[string]FieldRules{}
+	for _, path := range paths {
+		rules[path] = fieldRules(doc[path])
+	}
+
+	return func(o *Options) {
+		if o.FieldRules == nil {
+			o.FieldRules = map[string]FieldRules{}
+		}
+		for path, item := range rules {
+			o.FieldRules[path] = item
+		}
+	}, nil
+}
+
+// Reads the rules of a field, each rule is checked with the syntax of the tags and the invalid ones are kept in Errors
+func fieldRules(doc interface{}) FieldRules {
+	item := FieldRules{}
+	fields, ok := doc.(map[string]interface{})
+	if !ok {
+		item.Errors = append(item.Errors, fmt.Errorf("the override must be an object with the rules of the field"))
+		return item
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		set, ok := ruleKeywords[key]
+		if !ok {
+			item.Errors = append(item.Errors, fmt.Errorf("invalid keyword [%v], expected one of description, enum, items, itemsType, type or validation", key))
+			continue
+		}
+		val, err := ruleValue(key, fields[key])
+		if err == nil {
+			rule := FieldRules{}
+			set(&rule, val)
+			err = rule.override().Check()
+		}
+		if err != nil {
+			item.Errors = append(item.Errors, err)
+			continue
+		}
+		set(&item, val)
+	}
+	return item
+}
+
+// Gets the tag syntax of a rule, lists are written as comma separated values
+func ruleValue(key string, val interface{}) (string, error) {
+	switch item := val.(type) {
+	case string:
+		return item, nil
+	case []interface{}:
+		if key != "enum" && key != "type" && key != "itemsType" {
+			break
+		}
+		values := make([]string, 0, len(item))
+		for _, value := range item {
+			switch v := value.(type) {
+			case string:
+				if key == "enum" {
+					// Quoted so numbers and booleans in strings stay strings
+					v = "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
+				}
+				values = append(values, v)
+			case json.Number, bool, nil:
+				if key != "enum" {
+					return "", fmt.Errorf("invalid value of [%v], expected a list of strings", key)
+				}
+				values = append(values, fmt.Sprint(valueOrNull(v)))
+			default:
+				return "", fmt.Errorf("invalid value of [%v], expected a list of strings, numbers, booleans or null", key)
+			}
+		}
+		return strings.Join(values, ","), nil
+	}
+	if key == "enum" || key == "type" || key == "itemsType" {
+		return "", fmt.Errorf("invalid value of [%v], expected a string or a list", key)
+	}
+	return "", fmt.Errorf("invalid value of [%v], expected a string", key)
+}
+
+func valueOrNull(val interface{}) interface{} {
+	if val == nil {
+		return "null"
+	}
+	return val
+}
+
+// Gets the override of the rules of a field
+func (r FieldRules) override() validation.Override {
+	items := []string{}
+	for _, item := range [][2]string{{"enum", r.Enum}, {"description", r.Description}, {"type", r.Type}, {"itemsType", r.ItemsType}} {
+		if item[1] != "" {
+			items = append(items, fmt.Sprintf("%v:%q", item[0], item[1]))
+		}
+	}
+	return validation.Override{Tags: strings.Join(items, " "), Validation: r.Validation, Items: r.Items}
+}
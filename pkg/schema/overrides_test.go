@@ -0,0 +1,156 @@
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/s-augustovitko/mongo-schema-go/internal/validation"
+)
+
+type loadOverridesTestAddress struct {
+	Street string `bson:"street" validation:"max=100"`
+	Zip    string `bson:"zip" validation:"required,min=1"`
+}
+
+type loadOverridesTest struct {
+	Address loadOverridesTestAddress `bson:"address"`
+	Status  string                   `bson:"status" enum:"open"`
+	Codes   []int                    `bson:"codes"`
+	Note    string                   `bson:"note"`
+	Total   int                      `bson:"total" validation:"min=1,max=10"`
+}
+
+func TestLoadOverrides(t *testing.T) {
+	doc := `{
+		"address.zip": {"validation": "min=5,pattern=^\\d{5}$", "description": "Zip code"},
+		"address.street": {"validation": "max=64"},
+		"status": {"enum": ["active", "closed", "1"]},
+		"codes": {"items": "max=10", "itemsType": ["int", "null"], "enum": [1, 2, null], "type": ["array", "null"]},
+		"total": {"validation": "exclusiveMinimum=0"}
+	}`
+	opt, err := LoadOverrides(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("\nGot: %#v;\nWant: nil", err)
+	}
+
+	want := validation.BsonM{
+		"address": validation.BsonM{
+			"bsonType": []string{"object"},
+			"required": []string{"zip"},
+			"properties": validation.BsonM{
+				"street": validation.BsonM{"bsonType": []string{"string"}, "maxLength": 64},
+				"zip":    validation.BsonM{"bsonType": []string{"string"}, "minLength": 5, "pattern": `^\d{5}$`, "description": "Zip code"}}},
+		"status": validation.BsonM{"bsonType": []string{"string"}, "enum": []interface{}{"active", "closed", "1"}},
+		"codes": validation.BsonM{"bsonType": []string{"array", "null"}, "uniqueItems": false,
+			"items": validation.BsonM{"bsonType": []string{"int", "null"}, "maximum": float64(10), "enum": []interface{}{int32(1), int32(2), nil}}},
+		"note": validation.BsonM{"bsonType": []string{"string"}},
+		// The exclusive bound replaces the inclusive one of the tags
+		"total": validation.BsonM{"bsonType": []string{"int", "long"}, "minimum": float64(0), "exclusiveMinimum": true, "maximum": float64(10)},
+	}
+
+	out, warnings, err := For[loadOverridesTest](opt)
+	have := out["validator"].(validation.BsonM)["$jsonSchema"].(validation.BsonM)["properties"]
+	if err != nil || len(warnings) != 0 || !reflect.DeepEqual(have, want) {
+		t.Errorf("\nGot: %#v;\nWant: %#v;\nWarnings: %v;\nError: %v", have, want, warnings, err)
+	}
+
+	// Paths of the document that are not fields of the struct are warnings, the ones of WithFieldTags are still an error
+	opt, _ = LoadOverrides(strings.NewReader(`{"unknown": {"validation": "required"}, "note": {"description": "Note"}}`))
+	_, warnings, err = For[loadOverridesTest](opt, WithFieldTags("address.country", `validation:"required"`))
+	wantErr := UnknownPathError{Paths: []string{"address.country"}}
+	if !reflect.DeepEqual(err, wantErr) || len(warnings) != 1 || warnings[0].(validation.ErrorWithTag).Path() != "unknown" ||
+		warnings[0].Error() != "[unknown]: the override path is not a field of the struct" {
+		t.Errorf("\nGot: %#v;\nWant: %#v;\nWarnings: %v", err, wantErr, warnings)
+	}
+}
+
+type loadOverridesErrTest struct {
+	arg  string
+	want string
+}
+
+func TestLoadOverridesErr(t *testing.T) {
+	tests := []loadOverridesErrTest{
+		{``, "invalid overrides document, EOF"},
+		{`[]`, "invalid overrides document, json: cannot unmarshal array into Go value of type map[string]interface {}"},
+		{`{"a": }`, "invalid overrides document, invalid character '}' looking for beginning of value"},
+		{`{} {}`, "invalid overrides document, unexpected data after the object"},
+	}
+
+	for _, test := range tests {
+		opt, err := LoadOverrides(strings.NewReader(test.arg))
+		if opt != nil || err == nil || err.Error() != test.want {
+			t.Errorf("Document: %v;\nGot: %v;\nWant: %v", test.arg, err, test.want)
+		}
+	}
+}
+
+type loadOverridesWarningTest struct {
+	arg      string
+	want     validation.BsonM
+	wantPath string
+	wantErr  string
+}
+
+func TestLoadOverridesWarnings(t *testing.T) {
+	street := validation.BsonM{"bsonType": []string{"string"}, "maxLength": 100}
+	zip := validation.BsonM{"bsonType": []string{"string"}, "minLength": 1}
+	tests := []loadOverridesWarningTest{
+		{`{"address.zip": {"validation": "bogus=1", "description": "Zip code"}}`,
+			validation.BsonM{"street": street, "zip": validation.BsonM{"bsonType": []string{"string"}, "minLength": 1, "description": "Zip code"}},
+			"address.zip", "[address.zip]: invalid [validation] tag at offset 0: invalid validation [bogus]"},
+		{`{"address.street": {"validation": "max=64", "color": "red"}}`,
+			validation.BsonM{"street": validation.BsonM{"bsonType": []string{"string"}, "maxLength": 64}, "zip": zip},
+			"address.street", "[address.street]: invalid keyword [color], expected one of description, enum, items, itemsType, type or validation"},
+		{`{"address.street": {"type": 1}}`, validation.BsonM{"street": street, "zip": zip},
+			"address.street", "[address.street]: invalid value of [type], expected a string or a list"},
+		{`{"address.street": {"type": "text"}}`, validation.BsonM{"street": street, "zip": zip},
+			"address.street", "[address.street]: the following types are invalid [text]"},
+		{`{"address.zip": {"items": "min=1,exclusiveMinimum=2"}}`, validation.BsonM{"street": street, "zip": zip},
+			"address.zip", "[address.zip]: invalid [items] tag at offset 6: invalid [min,exclusiveMinimum] values, can not be used together"},
+		{`{"address.zip": {"enum": "'a"}}`, validation.BsonM{"street": street, "zip": zip},
+			"address.zip", "[address.zip]: invalid [enum] tag at offset 0: unterminated quote"},
+		{`{"address.zip": "required"}`, validation.BsonM{"street": street, "zip": zip},
+			"address.zip", "[address.zip]: the override must be an object with the rules of the field"},
+	}
+
+	for _, test := range tests {
+		opt, err := LoadOverrides(strings.NewReader(test.arg))
+		if err != nil {
+			t.Fatalf("Document: %v;\nGot: %v;\nWant: nil", test.arg, err)
+		}
+
+		out, warnings, err := For[loadOverridesTest](opt)
+		have := out["validator"].(validation.BsonM)["$jsonSchema"].(validation.BsonM)["properties"].(validation.BsonM)["address"].(validation.BsonM)["properties"]
+		if err != nil || !reflect.DeepEqual(have, test.want) || len(warnings) != 1 ||
+			warnings[0].(validation.ErrorWithTag).Path() != test.wantPath || warnings[0].Error() != test.wantErr {
+			t.Errorf("Document: %v;\nGot: %#v;\nWant: %#v;\nWarnings: %v;\nError: %v", test.arg, have, test.want, warnings, err)
+		}
+	}
+}
+
+type ruleValueTest struct {
+	arg1    string
+	arg2    interface{}
+	want    string
+	wantErr bool
+}
+
+func TestRuleValue(t *testing.T) {
+	tests := []ruleValueTest{
+		{"validation", "required", "required", false},
+		{"enum", []interface{}{"a,b", "it's", true}, `'a,b','it\'s',true`, false},
+		{"type", []interface{}{"string", "null"}, "string,null", false},
+		{"type", []interface{}{"string", 1}, "", true},
+		{"validation", []interface{}{"required"}, "", true},
+		{"description", true, "", true},
+	}
+
+	for _, test := range tests {
+		have, err := ruleValue(test.arg1, test.arg2)
+		if have != test.want || (err != nil) != test.wantErr {
+			t.Errorf("\nGot: %#v, %v;\nWant: %#v, %v", have, err, test.want, test.wantErr)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+package schema
+
+import "github.com/s-augustovitko/mongo-schema-go/internal/validation"
+
+// Implemented by types that describe their own schema, similar to json.Marshaler
+// the returned schema replaces the reflected one, the validation, description and enum tags are still added on top of it
+type SchemaProvider = validation.SchemaProvider
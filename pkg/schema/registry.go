@@ -0,0 +1,31 @@
+package schema
+
+import (
+	"reflect"
+
+	"github.com/s-augustovitko/mongo-schema-go/internal/tags"
+)
+
+// Registers the bson types used for every field of type typ
+// registered types are checked before the well known types and the reflect.Kind of the field
+func RegisterType(typ reflect.Type, bsonTypes ...string) error {
+	return tags.RegisterType(typ, bsonTypes...)
+}
+
+// Registers the bson types used for every field whose type has the package path and name
+// this is used to map types without importing their package, eg.
+// RegisterTypeName("go.mongodb.org/mongo-driver/bson/primitive", "ObjectID", "objectId")
+func RegisterTypeName(pkgPath, name string, bsonTypes ...string) error {
+	return tags.RegisterTypeName(pkgPath, name, bsonTypes...)
+}
+
+// Registers the values allowed for every field of type T, eg. the constants of a named string type
+// the values are used as the enum of the fields without an enum tag, and for the items of arrays and maps
+// schema-enum can generate the registration from the constants of the package
+func RegisterEnum[T any](values ...T) error {
+	reflectValues := make([]reflect.Value, 0, len(values))
+	for _, val := range values {
+		reflectValues = append(reflectValues, reflect.ValueOf(val))
+	}
+	return tags.RegisterEnum(reflect.TypeOf((*T)(nil)).Elem(), reflectValues...)
+}
@@ -0,0 +1,115 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/s-augustovitko/mongo-schema-go/internal/validation"
+)
+
+type testObjectID [12]byte
+
+type testDecimal struct {
+	h, l uint64
+}
+
+type registryTest struct {
+	ID      testObjectID   `bson:"_id"`
+	Price   testDecimal    `bson:"price" validation:"required"`
+	Related []testObjectID `bson:"related"`
+}
+
+func TestRegisterType(t *testing.T) {
+	if err := RegisterType(reflect.TypeOf(testObjectID{}), "objectId"); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterTypeName(reflect.TypeOf(testDecimal{}).PkgPath(), "testDecimal", "decimal"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := validation.BsonM{
+		"_id":   validation.BsonM{"bsonType": []string{"objectId"}},
+		"price": validation.BsonM{"bsonType": []string{"decimal"}},
+		"related": validation.BsonM{
+			"bsonType":    []string{"array"},
+			"items":       validation.BsonM{"bsonType": []string{"objectId"}},
+			"uniqueItems": false},
+	}
+
+	out, warnings, err := For[registryTest]()
+	have := out["validator"].(validation.BsonM)["$jsonSchema"].(validation.BsonM)["properties"]
+
+	if err != nil || len(warnings) > 0 || !reflect.DeepEqual(want, have) {
+		t.Errorf("\nGot: %#v;\nWant: %#v;\nWarns: %#v;\nErr: %#v;", have, want, warnings, err)
+	}
+}
+
+type testStatus string
+
+const (
+	testStatusOpen   testStatus = "open"
+	testStatusClosed testStatus = "closed"
+)
+
+type testPriority int
+
+type registryEnumTest struct {
+	Status     testStatus            `bson:"status"`
+	Priority   testPriority          `bson:"priority"`
+	Statuses   []testStatus          `bson:"statuses"`
+	ByPriority map[string]testStatus `bson:"byPriority"`
+	Override   testStatus            `bson:"override" enum:"draft"`
+}
+
+type registryEnumNullableTest struct {
+	Status   *testStatus  `bson:"status"`
+	Statuses []testStatus `bson:"statuses"`
+}
+
+func TestRegisterEnum(t *testing.T) {
+	if err := RegisterEnum(testStatusOpen, testStatusClosed); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterEnum[testPriority](1, 2, 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterEnum[struct{}](struct{}{}); err == nil {
+		t.Errorf("\nGot: %#v;\nWant: an error", err)
+	}
+
+	enum := []interface{}{"open", "closed"}
+	want := validation.BsonM{
+		"status":   validation.BsonM{"bsonType": []string{"string"}, "enum": enum},
+		"priority": validation.BsonM{"bsonType": []string{"int", "long"}, "enum": []interface{}{int32(1), int32(2), int32(3)}},
+		"statuses": validation.BsonM{
+			"bsonType":    []string{"array"},
+			"items":       validation.BsonM{"bsonType": []string{"string"}, "enum": enum},
+			"uniqueItems": false},
+		"byPriority": validation.BsonM{
+			"bsonType":             []string{"object"},
+			"additionalProperties": validation.BsonM{"bsonType": []string{"string"}, "enum": enum}},
+		"override": validation.BsonM{"bsonType": []string{"string"}, "enum": []interface{}{"draft"}},
+	}
+
+	out, warnings, err := For[registryEnumTest]()
+	have := out["validator"].(validation.BsonM)["$jsonSchema"].(validation.BsonM)["properties"]
+
+	if err != nil || len(warnings) > 0 || !reflect.DeepEqual(want, have) {
+		t.Errorf("\nGot: %#v;\nWant: %#v;\nWarns: %#v;\nErr: %#v;", have, want, warnings, err)
+	}
+
+	nullable := validation.BsonM{
+		"status": validation.BsonM{"bsonType": []string{"string", "null"}, "enum": []interface{}{"open", "closed", nil}},
+		"statuses": validation.BsonM{
+			"bsonType":    []string{"array", "null"},
+			"items":       validation.BsonM{"bsonType": []string{"string"}, "enum": enum},
+			"uniqueItems": false},
+	}
+
+	out, warnings, err = For[registryEnumNullableTest](Nullable())
+	have = out["validator"].(validation.BsonM)["$jsonSchema"].(validation.BsonM)["properties"]
+
+	if err != nil || len(warnings) > 0 || !reflect.DeepEqual(nullable, have) {
+		t.Errorf("\nGot: %#v;\nWant: %#v;\nWarns: %#v;\nErr: %#v;", have, nullable, warnings, err)
+	}
+}
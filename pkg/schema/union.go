@@ -0,0 +1,15 @@
+package schema
+
+import (
+	"reflect"
+
+	"github.com/s-augustovitko/mongo-schema-go/internal/validation"
+)
+
+// Registers the variants of an interface type, by the value of their discriminator field
+// fields, array items and map values of the interface are described as a oneOf of the object schemas of the variants
+// where the discriminator field of each variant only allows its value, eg.
+// RegisterUnion(reflect.TypeOf((*Payload)(nil)).Elem(), "kind", map[string]reflect.Type{"created": reflect.TypeOf(Created{})})
+func RegisterUnion(iface reflect.Type, discriminatorField string, variants map[string]reflect.Type) error {
+	return validation.RegisterUnion(iface, discriminatorField, variants)
+}